@@ -0,0 +1,187 @@
+package board
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// alphabetKeyword starts alphabet specification in the first line of serialized board.
+const alphabetKeyword = "symbols:"
+
+// Alphabet is a set of symbols used instead of decimal numbers when board is parsed
+// and printed (see SetAlphabet) - number n is written as the n-th symbol of the alphabet
+// and empty field as ".". Each symbol is a single letter or digit, so fields do not have
+// to be padded and large boards are written the same way as they are published.
+type Alphabet struct {
+	symbols []rune // symbols[n-1] is symbol of number n
+	numbers map[rune]uint16
+}
+
+var (
+	// Alphanumeric has digits 1-9 followed by letters, e.g. 1-9A-G is used for 16x16 board.
+	Alphanumeric = mustNewAlphabet("1-9A-Z")
+	// Letters has only letters, e.g. A-Y is used for 25x25 board.
+	Letters = mustNewAlphabet("A-Z")
+)
+
+// NewAlphabet creates alphabet from its specification - list of symbols, in which
+// ranges of consecutive symbols can be given as "<first>-<last>". For example, both
+// "1-9A-G" and "123456789ABCDEFG" are the same alphabet for boards up to 16x16.
+// Symbols must be letters or digits and they must not repeat. Boards can use only
+// the first symbols of larger alphabets, "." is always used for empty fields.
+// Digit 0 is read as empty field as well, unless it is a symbol of the alphabet.
+func NewAlphabet(spec string) (*Alphabet, error) {
+	runes := []rune(spec)
+	a := &Alphabet{numbers: make(map[rune]uint16)}
+	for i := 0; i < len(runes); i++ {
+		first, last := runes[i], runes[i]
+		if i+2 < len(runes) && runes[i+1] == '-' {
+			last = runes[i+2]
+			i += 2
+		}
+		if first > last {
+			return nil, fmt.Errorf("invalid range %c-%c in alphabet %q", first, last, spec)
+		}
+		for r := first; r <= last; r++ {
+			if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+				return nil, fmt.Errorf("invalid symbol %q in alphabet %q, symbols must be letters or digits", r, spec)
+			}
+			if _, ok := a.numbers[r]; ok {
+				return nil, fmt.Errorf("symbol %c repeats in alphabet %q", r, spec)
+			}
+			if len(a.symbols) == MaxSize {
+				return nil, fmt.Errorf("alphabet %q has more than %d symbols", spec, MaxSize)
+			}
+			a.symbols = append(a.symbols, r)
+			a.numbers[r] = uint16(len(a.symbols))
+		}
+	}
+	if len(a.symbols) == 0 {
+		return nil, fmt.Errorf("alphabet must have at least 1 symbol")
+	}
+	return a, nil
+}
+
+func mustNewAlphabet(spec string) *Alphabet {
+	a, err := NewAlphabet(spec)
+	if err != nil {
+		panic(err)
+	}
+	return a
+}
+
+// Size returns number of symbols, i.e. the largest board size which can use the alphabet.
+func (a *Alphabet) Size() int {
+	return len(a.symbols)
+}
+
+// Symbol returns symbol of number n ("." for 0). n must not be larger than Size.
+func (a *Alphabet) Symbol(n uint16) string {
+	if n == 0 {
+		return "."
+	}
+	return string(a.symbols[n-1])
+}
+
+// Number returns number written as symbol r (0 for "." and "0" if it is not a symbol of
+// the alphabet). It returns false if r is not a symbol.
+func (a *Alphabet) Number(r rune) (uint16, bool) {
+	if n, ok := a.numbers[r]; ok {
+		return n, true
+	}
+	if r == '.' || r == '0' {
+		return 0, true
+	}
+	return 0, false
+}
+
+// String returns specification of the alphabet (see NewAlphabet), in which at least
+// 3 consecutive symbols are written as a range, e.g. "1-9A-G".
+func (a *Alphabet) String() string {
+	var s strings.Builder
+	for i := 0; i < len(a.symbols); {
+		j := i
+		for j+1 < len(a.symbols) && a.symbols[j+1] == a.symbols[j]+1 {
+			j++
+		}
+		if j-i >= 2 {
+			fmt.Fprintf(&s, "%c-%c", a.symbols[i], a.symbols[j])
+			i = j + 1
+		} else {
+			s.WriteRune(a.symbols[i])
+			i++
+		}
+	}
+	return s.String()
+}
+
+// parseLine returns numbers of all symbols in line, other characters are ignored.
+func (a *Alphabet) parseLine(line string) []int {
+	var numbers []int
+	for _, r := range line {
+		if n, ok := a.Number(r); ok {
+			numbers = append(numbers, int(n))
+		}
+	}
+	return numbers
+}
+
+// SetAlphabet sets alphabet used by String and Serialize (and kept by Copy, so solutions
+// are printed with the same symbols), nil restores decimal numbers. It returns error
+// if the alphabet has fewer symbols than the board size. Equal ignores the alphabet.
+// Serialized board with alphabet has its specification at the end of the first line, e.g.:
+// 4 4 symbols: 1-9A-Z
+// Then board data is read with symbols (see Alphabet) instead of decimal numbers.
+func (b *Board) SetAlphabet(a *Alphabet) error {
+	if a != nil && a.Size() < b.gridSize {
+		return fmt.Errorf("alphabet %s has %d symbols, board size is %d", a, a.Size(), b.gridSize)
+	}
+	b.alphabet = a
+	return nil
+}
+
+// Alphabet returns alphabet of the board (see SetAlphabet), nil if decimal numbers are used.
+func (b *Board) Alphabet() *Alphabet {
+	return b.alphabet
+}
+
+// splitAlphabetHeader splits the first line of serialized board into the header and
+// alphabet specified at its end (nil if there is none).
+func splitAlphabetHeader(line string) (string, *Alphabet, error) {
+	i := strings.Index(line, alphabetKeyword)
+	if i < 0 {
+		return line, nil, nil
+	}
+	a, err := NewAlphabet(strings.TrimSpace(line[i+len(alphabetKeyword):]))
+	if err != nil {
+		return "", nil, fmt.Errorf("error parsing symbols in line: %s: %w", line, err)
+	}
+	return line[:i], a, nil
+}
+
+// alphabetHeaderSuffix returns suffix of the first line of serialized board
+// specifying its alphabet (empty if decimal numbers are used).
+func (b *Board) alphabetHeaderSuffix() string {
+	if b.alphabet == nil {
+		return ""
+	}
+	return " " + alphabetKeyword + " " + b.alphabet.String()
+}
+
+// formatNumber returns number written with the alphabet of the board, padded to width
+// (see numberWidth).
+func (b *Board) formatNumber(n uint16, width int) string {
+	if b.alphabet != nil {
+		return b.alphabet.Symbol(n)
+	}
+	return fmt.Sprintf("%*d", width, n)
+}
+
+// numberWidth returns length of the longest number written by formatNumber.
+func (b *Board) numberWidth() int {
+	if b.alphabet != nil {
+		return 1
+	}
+	return len(fmt.Sprint(b.gridSize))
+}
@@ -0,0 +1,146 @@
+package board_test
+
+import (
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tomaszmj/sudoku/board"
+)
+
+func TestNewAlphabet(t *testing.T) {
+	for _, tc := range []struct {
+		spec         string
+		expectedSize int
+		expectedSpec string
+	}{
+		{"1-9A-G", 16, "1-9A-G"},
+		{"123456789ABCDEFG", 16, "1-9A-G"},
+		{"A-Y", 25, "A-Y"},
+		{"XO", 2, "XO"},
+		{"ABDC", 4, "ABDC"},
+		{"0-9a-f", 16, "0-9a-f"},
+	} {
+		a, err := board.NewAlphabet(tc.spec)
+		require.NoError(t, err, tc.spec)
+		assert.Equal(t, tc.expectedSize, a.Size(), tc.spec)
+		assert.Equal(t, tc.expectedSpec, a.String(), tc.spec)
+	}
+
+	for _, spec := range []string{"", "1-9 A-G", "AA", "1-9A-F5", "Z-A", "-", "1+2", "A."} {
+		_, err := board.NewAlphabet(spec)
+		assert.Error(t, err, spec)
+	}
+
+	a, err := board.NewAlphabet("1-9A-G")
+	require.NoError(t, err)
+	assert.Equal(t, "G", a.Symbol(16))
+	assert.Equal(t, ".", a.Symbol(0))
+	for _, tc := range []struct {
+		symbol         rune
+		expectedNumber uint16
+		expectedOk     bool
+	}{
+		{'A', 10, true},
+		{'1', 1, true},
+		{'.', 0, true},
+		{'0', 0, true},
+		{'H', 0, false},
+		{'|', 0, false},
+	} {
+		n, ok := a.Number(tc.symbol)
+		assert.Equal(t, tc.expectedNumber, n, string(tc.symbol))
+		assert.Equal(t, tc.expectedOk, ok, string(tc.symbol))
+	}
+}
+
+func TestBoardAlphabet(t *testing.T) {
+	t.Run("16x16 with symbols", func(t *testing.T) {
+		file, err := os.Open("../cmd/boards/16x16_symbols.txt")
+		require.NoError(t, err)
+		defer file.Close()
+		b, err := board.NewFromSerializedFormat(file)
+		require.NoError(t, err)
+		require.NotNil(t, b.Alphabet())
+		assert.Equal(t, "1-9A-G", b.Alphabet().String())
+		assert.Equal(t, uint16(16), b.Get(4, 0))
+		assert.Equal(t, uint16(0), b.Get(0, 0))
+
+		file2, err := os.Open("../cmd/boards/16x16.txt")
+		require.NoError(t, err)
+		defer file2.Close()
+		b2, err := board.NewFromSerializedFormat(file2)
+		require.NoError(t, err)
+		assert.True(t, b.Equal(b2))
+		assert.Nil(t, b2.Alphabet())
+
+		assert.True(t, strings.HasPrefix(b.String(), "+---------+---------+---------+---------+\n| . . . 2 | G 8 7 . |"))
+		assert.Equal(t, b.Alphabet(), b.Copy().Alphabet())
+	})
+
+	t.Run("serialize round trip", func(t *testing.T) {
+		b, err := board.New(5, 5)
+		require.NoError(t, err)
+		b.Set(0, 0, 1)
+		b.Set(24, 24, 25)
+		b.SetDiagonal(true)
+		require.NoError(t, b.SetAlphabet(board.Letters))
+		var s strings.Builder
+		require.NoError(t, b.Serialize(&s))
+		assert.True(t, strings.HasPrefix(s.String(), "5 5 diagonal symbols: A-Z\n"))
+		assert.Contains(t, s.String(), "| A . . . . |")
+		assert.Contains(t, s.String(), "| . . . . Y |")
+		b2, err := board.NewFromSerializedFormat(strings.NewReader(s.String()))
+		require.NoError(t, err)
+		assert.Equal(t, b, b2)
+	})
+
+	t.Run("jigsaw", func(t *testing.T) {
+		b, err := board.NewJigsaw([][]int{{0, 0, 1}, {0, 2, 1}, {2, 2, 1}})
+		require.NoError(t, err)
+		b.Set(1, 0, 3)
+		a, err := board.NewAlphabet("XYZ")
+		require.NoError(t, err)
+		require.NoError(t, b.SetAlphabet(a))
+		var s strings.Builder
+		require.NoError(t, b.Serialize(&s))
+		assert.True(t, strings.HasPrefix(s.String(), "jigsaw 3 symbols: X-Z\n"))
+		assert.Contains(t, s.String(), "| .   Z | . |")
+		b2, err := board.NewFromSerializedFormat(strings.NewReader(s.String()))
+		require.NoError(t, err)
+		assert.Equal(t, b, b2)
+	})
+
+	t.Run("compact rows", func(t *testing.T) {
+		b, err := board.NewFromSerializedFormat(strings.NewReader("2 2 symbols: ABCD\nAB..\n.0..\n....\n...D\n"))
+		require.NoError(t, err)
+		assert.Equal(t, uint16(2), b.Get(1, 0))
+		assert.Equal(t, uint16(4), b.Get(3, 3))
+	})
+
+	t.Run("invalid", func(t *testing.T) {
+		for _, tc := range []struct {
+			input         string
+			expectedError string
+		}{
+			{"2 2 symbols: A\n", "alphabet A has 1 symbols, board size is 4"},
+			{"jigsaw 2 symbols: A\n", "alphabet A has 1 symbols, board size is 2"},
+			{"2 2 symbols: A+B\n", "error parsing symbols"},
+			{"2 2 symbols: A-D\nABCD\nABCD\nABC\nABCD\n", "expected 4 numbers, got 3 in line 4"},
+			{"2 2 symbols: A-E\nABCD\nABCD\nABCE\nABCD\n", "in line 4"},
+		} {
+			_, err := board.NewFromSerializedFormat(strings.NewReader(tc.input))
+			require.Error(t, err, tc.input)
+			assert.Contains(t, err.Error(), tc.expectedError, tc.input)
+		}
+
+		b, err := board.New(3, 3)
+		require.NoError(t, err)
+		a, err := board.NewAlphabet("A-H")
+		require.NoError(t, err)
+		assert.Error(t, b.SetAlphabet(a))
+		assert.Nil(t, b.Alphabet())
+	})
+}
@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"math"
+	"reflect"
 	"regexp"
 	"strconv"
 	"strings"
@@ -19,6 +20,20 @@ type Board struct {
 	gridSize       int
 	subgridsCountX int
 	subgridsCountY int
+	// regions and regionFields are set only for jigsaw boards (see NewJigsaw), in which subgrids
+	// are irregular - then subgridWidth, subgridHeight, subgridsCountX, subgridsCountY are 0.
+	// They are never modified, so they can be shared between board copies.
+	regions      []int     // subgrid (region) index of each field
+	regionFields [][]Field // fields of each region
+	diagonal     bool      // diagonal (X-Sudoku) constraint, see SetDiagonal
+	cages        []Cage    // Killer Sudoku cages, see AddCage
+	cageOfField  []int     // cage index of each field (-1 if it is not in any cage), nil if there are no cages
+	// constraints are additional rules of sudoku variants, see AddConstraint
+	constraints        []Constraint
+	constraintsOfField [][]int   // indexes of constraints covering each field, nil if there are no constraints
+	givens             []bool    // fields marked as given (see SetGiven), nil if there are none
+	alphabet           *Alphabet // symbols used by String and Serialize (see SetAlphabet), nil for decimal numbers
+	metadata           Metadata
 }
 
 // New creates board with given SUBGRID width and height.
@@ -81,20 +96,43 @@ var findNumbersRegex = regexp.MustCompile(`\d+`)
 // 4 2 3 1
 // 1 3 4 2
 // some random comment not containing digits
+// If the first line contains word "diagonal", diagonal constraint is enabled
+// on the board (see SetDiagonal), for example:
+// 3 3 diagonal
+// The first line can end with alphabet specification (see SetAlphabet), for example:
+// 4 4 symbols: 1-9A-G
+// Board data can be followed by Killer Sudoku cages (see AddCage), in format described in scanCages.
+// One-line format (see NewFromLine) is accepted as well - then the whole board is in the first line.
 func NewFromSerializedFormat(reader io.Reader) (*Board, error) {
 	scanner := bufio.NewScanner(reader)
 	if !scanner.Scan() {
 		return nil, fmt.Errorf("error - no data")
 	}
-	firstLineNumbers := findNumbersRegex.FindAll(scanner.Bytes(), 3) // 3 instead of 2 to find if there are too many numbers
+	if isLine(scanner.Text()) {
+		return newFromLineScanner(scanner)
+	}
+	header, alphabet, err := splitAlphabetHeader(scanner.Text())
+	if err != nil {
+		return nil, err
+	}
+	diagonal := strings.Contains(header, diagonalKeyword)
+	if strings.Contains(header, jigsawKeyword) {
+		board, err := newJigsawFromSerializedFormat(scanner, header, alphabet)
+		if err != nil {
+			return nil, err
+		}
+		board.diagonal = diagonal
+		return board, nil
+	}
+	firstLineNumbers := findNumbersRegex.FindAllString(header, 3) // 3 instead of 2 to find if there are too many numbers
 	if len(firstLineNumbers) != 2 {
 		return nil, fmt.Errorf("error parsing - expected 2 numbers, line: %s", scanner.Text())
 	}
-	subgridWidth, err := strconv.Atoi(string(firstLineNumbers[0]))
+	subgridWidth, err := strconv.Atoi(firstLineNumbers[0])
 	if err != nil {
 		return nil, fmt.Errorf("error parsing number %w in line: %s", err, scanner.Text())
 	}
-	subgridHeight, err := strconv.Atoi(string(firstLineNumbers[1]))
+	subgridHeight, err := strconv.Atoi(firstLineNumbers[1])
 	if err != nil {
 		return nil, fmt.Errorf("error parsing number %w in line: %s", err, scanner.Text())
 	}
@@ -102,47 +140,107 @@ func NewFromSerializedFormat(reader io.Reader) (*Board, error) {
 	if err != nil {
 		return nil, fmt.Errorf("error creating board: %w", err)
 	}
-	y, lineNumber := 0, 1 // y is number of saved rows in the board, lineNumber is number of lines read (only for error reporting)
-	for scanner.Scan() {
-		lineNumber++
-		numbers := findNumbersRegex.FindAll(scanner.Bytes(), board.gridSize+1) // +1 to find if there are too many numbers
-		if len(numbers) == 0 {
-			continue
+	if err := board.SetAlphabet(alphabet); err != nil {
+		return nil, err
+	}
+	lines := &numberLinesScanner{scanner: scanner, lineNumber: 1}
+	rows, err := lines.scanRows("board", board.gridSize, board.gridSize, alphabet)
+	if err != nil {
+		return nil, err
+	}
+	cages, err := lines.scanCages("board", board.gridSize)
+	if err != nil {
+		return nil, err
+	}
+	board.setRows(rows)
+	board.diagonal = diagonal
+	if err := board.addCages(cages); err != nil {
+		return nil, err
+	}
+	return board, nil
+}
+
+// numberLinesScanner reads lines containing numbers, skipping lines without numbers.
+type numberLinesScanner struct {
+	scanner    *bufio.Scanner
+	lineNumber int // number of lines read (only for error reporting)
+}
+
+// scanRows reads gridSize lines, each with gridSize numbers from 0 to maxNumber.
+// Numbers are written with symbols of the alphabet, or as decimal numbers if it is nil.
+// Name is used only for error reporting.
+func (s *numberLinesScanner) scanRows(name string, gridSize, maxNumber int, alphabet *Alphabet) ([][]int, error) {
+	rows := make([][]int, 0, gridSize)
+	for len(rows) < gridSize && s.scanner.Scan() {
+		s.lineNumber++
+		var row []int
+		var err error
+		if alphabet != nil {
+			row = alphabet.parseLine(s.scanner.Text())
+		} else if row, err = s.parseNumbers(); err != nil {
+			return nil, err
 		}
-		if y >= board.gridSize { // we check it as late as here, because there might be lines without numbers at the end
-			return nil, fmt.Errorf("too many board lines, expected %d", board.gridSize)
+		if len(row) == 0 {
+			continue
 		}
-		if len(numbers) != board.gridSize {
-			return nil, fmt.Errorf("expected %d numbers, got %d in line %d: %s", board.gridSize, len(numbers), lineNumber, scanner.Text())
+		if len(row) != gridSize {
+			return nil, fmt.Errorf("expected %d numbers, got %d in line %d: %s", gridSize, len(row), s.lineNumber, s.scanner.Text())
 		}
-		for x, numberBytes := range numbers {
-			number, err := strconv.Atoi(string(numberBytes))
-			if err != nil {
-				return nil, fmt.Errorf("error parsing number %w in line %d: %s", err, lineNumber, scanner.Text())
-			}
-			if number < 0 || number > board.gridSize {
-				return nil, fmt.Errorf("inalid number %d in line %d: %s", number, lineNumber, scanner.Text())
+		for _, number := range row {
+			if number < 0 || number > maxNumber {
+				return nil, fmt.Errorf("inalid number %d in line %d: %s", number, s.lineNumber, s.scanner.Text())
 			}
-			board.Set(x, y, uint16(number))
 		}
-		y++
+		rows = append(rows, row)
 	}
-	if y != board.gridSize {
-		return nil, fmt.Errorf("invalid number of board lines, expected %d, got %d", board.gridSize, y)
+	if len(rows) != gridSize {
+		return nil, fmt.Errorf("invalid number of %s lines, expected %d, got %d", name, gridSize, len(rows))
+	}
+	return rows, nil
+}
+
+// parseNumbers returns all numbers from the current line.
+func (s *numberLinesScanner) parseNumbers() ([]int, error) {
+	numbersBytes := findNumbersRegex.FindAll(s.scanner.Bytes(), -1)
+	numbers := make([]int, 0, len(numbersBytes))
+	for _, numberBytes := range numbersBytes {
+		number, err := strconv.Atoi(string(numberBytes))
+		if err != nil {
+			return nil, fmt.Errorf("error parsing number %w in line %d: %s", err, s.lineNumber, s.scanner.Text())
+		}
+		numbers = append(numbers, number)
+	}
+	return numbers, nil
+}
+
+func (b *Board) setRows(rows [][]int) {
+	for y, row := range rows {
+		for x, number := range row {
+			b.Set(x, y, uint16(number))
+		}
 	}
-	return board, nil
 }
 
 func (b *Board) Copy() *Board {
 	dataCopy := make([]uint16, len(b.data))
 	copy(dataCopy, b.data)
 	return &Board{
-		data:           dataCopy,
-		subgridWidth:   b.subgridWidth,
-		subgridHeight:  b.subgridHeight,
-		gridSize:       b.gridSize,
-		subgridsCountX: b.subgridsCountX,
-		subgridsCountY: b.subgridsCountY,
+		data:               dataCopy,
+		subgridWidth:       b.subgridWidth,
+		subgridHeight:      b.subgridHeight,
+		gridSize:           b.gridSize,
+		subgridsCountX:     b.subgridsCountX,
+		subgridsCountY:     b.subgridsCountY,
+		regions:            b.regions,
+		regionFields:       b.regionFields,
+		diagonal:           b.diagonal,
+		cages:              append([]Cage(nil), b.cages...),
+		cageOfField:        append([]int(nil), b.cageOfField...),
+		constraints:        append([]Constraint(nil), b.constraints...),
+		constraintsOfField: b.copyConstraintsOfField(),
+		givens:             append([]bool(nil), b.givens...),
+		alphabet:           b.alphabet,
+		metadata:           b.metadata.copy(),
 	}
 }
 
@@ -165,13 +263,22 @@ func (b *Board) Set(x, y int, value uint16) {
 	b.data[offset] = value
 }
 
+// Equal returns true if both boards have the same numbers and rules (subgrids or regions,
+// diagonals, cages and constraints). Constraints are compared by value (with reflect.DeepEqual),
+// in the order in which they were added - constraints of the same type with equal fields
+// (or values pointed by their fields) are equal. Because of that, constraints should not
+// hold funcs, otherwise the board is not equal even to its copy.
 func (b *Board) Equal(b2 *Board) bool {
 	if b.subgridWidth != b2.subgridWidth ||
 		b.subgridHeight != b2.subgridHeight ||
 		b.gridSize != b2.gridSize ||
 		b.subgridsCountX != b2.subgridsCountX ||
 		b.subgridsCountY != b2.subgridsCountY ||
-		len(b.data) != len(b2.data) {
+		len(b.data) != len(b2.data) ||
+		len(b.regions) != len(b2.regions) ||
+		b.diagonal != b2.diagonal ||
+		len(b.cages) != len(b2.cages) ||
+		!reflect.DeepEqual(b.constraints, b2.constraints) {
 		return false
 	}
 	for i := range b.data {
@@ -179,6 +286,22 @@ func (b *Board) Equal(b2 *Board) bool {
 			return false
 		}
 	}
+	for i := range b.regions {
+		if b.regions[i] != b2.regions[i] {
+			return false
+		}
+	}
+	for i, cage := range b.cages {
+		cage2 := b2.cages[i]
+		if cage.Sum != cage2.Sum || len(cage.Fields) != len(cage2.Fields) {
+			return false
+		}
+		for j := range cage.Fields {
+			if cage.Fields[j] != cage2.Fields[j] {
+				return false
+			}
+		}
+	}
 	return true
 }
 
@@ -190,7 +313,20 @@ func (b *Board) ForEach(operation func(x, y int, n uint16)) {
 	}
 }
 
+// ForEachNeighbour calls operation once for each field, which cannot contain the same number
+// as field x0, y0, i.e. fields in the same row, column, subgrid, diagonal (if diagonal
+// constraint is enabled) or cage (see AddCage).
 func (b *Board) ForEachNeighbour(x0, y0 int, operation func(x, y int)) {
+	if b.regions != nil {
+		b.forEachJigsawNeighbour(x0, y0, operation)
+	} else {
+		b.forEachRectangularNeighbour(x0, y0, operation)
+	}
+	b.forEachDiagonalNeighbour(x0, y0, operation)
+	b.forEachCageNeighbour(x0, y0, operation)
+}
+
+func (b *Board) forEachRectangularNeighbour(x0, y0 int, operation func(x, y int)) {
 	gridBeginX := x0 - x0%b.subgridWidth
 	gridBeginY := y0 - y0%b.subgridHeight
 	gridEndX := gridBeginX + b.subgridWidth
@@ -251,23 +387,157 @@ func (b *Board) Validate(validate func(x, y int, n uint16) error, nextFieldGroup
 	}
 
 	// for each subgrid
+	for _, fields := range b.subgrids() {
+		for _, f := range fields {
+			if err := validate(f.X, f.Y, b.Get(f.X, f.Y)); err != nil {
+				return err
+			}
+		}
+		nextFieldGroup()
+	}
+
+	// for each diagonal (if diagonal constraint is enabled)
+	for _, fields := range b.diagonals() {
+		for _, f := range fields {
+			if err := validate(f.X, f.Y, b.Get(f.X, f.Y)); err != nil {
+				return err
+			}
+		}
+		nextFieldGroup()
+	}
+
+	// for each cage - fields are validated just like in other groups, then cage sum is checked
+	for i, cage := range b.cages {
+		for _, f := range cage.Fields {
+			if err := validate(f.X, f.Y, b.Get(f.X, f.Y)); err != nil {
+				return err
+			}
+		}
+		nextFieldGroup()
+		if b.cageSumBroken(cage) {
+			return fmt.Errorf("numbers in cage %d do not add up to %d", i, cage.Sum)
+		}
+	}
+
+	// constraints (see AddConstraint) are not groups of fields, they are just checked
+	if err := b.CheckConstraints(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// UnitKind describes kind of the group of fields (Unit),
+// in which each number can be placed only once.
+type UnitKind int
+
+const (
+	Row UnitKind = iota
+	Column
+	Subgrid
+	Diagonal
+	KillerCage     // used only in Violations, cages are not units (see AddCage)
+	ConstraintUnit // used only in Violations, constraints are not units (see AddConstraint)
+)
+
+func (k UnitKind) String() string {
+	switch k {
+	case Row:
+		return "row"
+	case Column:
+		return "column"
+	case Subgrid:
+		return "subgrid"
+	case Diagonal:
+		return "diagonal"
+	case KillerCage:
+		return "cage"
+	case ConstraintUnit:
+		return "constraint"
+	}
+	return fmt.Sprintf("UnitKind(%d)", int(k))
+}
+
+// Field holds coordinates of a single field on the board.
+type Field struct {
+	X, Y int
+}
+
+// Unit is a group of fields (row, column, subgrid or diagonal), in which each number
+// must be placed exactly once in a solved board.
+type Unit struct {
+	Kind   UnitKind
+	Index  int
+	Fields []Field
+}
+
+// Units returns all units of the board - first rows, then columns, then subgrids,
+// then diagonals if diagonal constraint is enabled (the same order in which Validate
+// visits them). Subgrids are indexed from left to right, from top to bottom.
+func (b *Board) Units() []Unit {
+	units := make([]Unit, 0, 3*b.gridSize+2)
+	for y := 0; y < b.gridSize; y++ {
+		fields := make([]Field, 0, b.gridSize)
+		for x := 0; x < b.gridSize; x++ {
+			fields = append(fields, Field{x, y})
+		}
+		units = append(units, Unit{Kind: Row, Index: y, Fields: fields})
+	}
+	for x := 0; x < b.gridSize; x++ {
+		fields := make([]Field, 0, b.gridSize)
+		for y := 0; y < b.gridSize; y++ {
+			fields = append(fields, Field{x, y})
+		}
+		units = append(units, Unit{Kind: Column, Index: x, Fields: fields})
+	}
+	for index, fields := range b.subgrids() {
+		units = append(units, Unit{Kind: Subgrid, Index: index, Fields: fields})
+	}
+	for index, fields := range b.diagonals() {
+		units = append(units, Unit{Kind: Diagonal, Index: index, Fields: fields})
+	}
+	return units
+}
+
+// subgrids returns fields of each subgrid. Subgrids are indexed from left to right,
+// from top to bottom (jigsaw regions are indexed as given in NewJigsaw).
+func (b *Board) subgrids() [][]Field {
+	if b.regions != nil {
+		return b.regionFields
+	}
+	subgrids := make([][]Field, 0, b.gridSize)
 	for y0 := 0; y0 < b.gridSize; y0 += b.subgridHeight {
 		for x0 := 0; x0 < b.gridSize; x0 += b.subgridWidth {
+			fields := make([]Field, 0, b.gridSize)
 			for y := y0; y < y0+b.subgridHeight; y++ {
 				for x := x0; x < x0+b.subgridWidth; x++ {
-					if err := validate(x, y, b.Get(x, y)); err != nil {
-						return err
-					}
+					fields = append(fields, Field{x, y})
 				}
 			}
-			nextFieldGroup()
+			subgrids = append(subgrids, fields)
 		}
 	}
+	return subgrids
+}
 
-	return nil
+// Subgrid returns index of the subgrid (region) containing field x, y
+// (in the same order as in Units).
+func (b *Board) Subgrid(x, y int) int {
+	if b.regions != nil {
+		return b.regions[y*b.gridSize+x]
+	}
+	return (y/b.subgridHeight)*b.subgridsCountX + x/b.subgridWidth
+}
+
+// IsJigsaw returns true if the board has irregular subgrids (see NewJigsaw).
+func (b *Board) IsJigsaw() bool {
+	return b.regions != nil
 }
 
 func (b *Board) HaveCommonSubgrid(x1, y1, x2, y2 int) bool {
+	if b.regions != nil {
+		return b.regions[y1*b.gridSize+x1] == b.regions[y2*b.gridSize+x2]
+	}
 	gridBeginX1 := x1 - x1%b.subgridWidth
 	gridBeginX2 := x2 - x2%b.subgridWidth
 	if gridBeginX1 != gridBeginX2 {
@@ -278,14 +548,25 @@ func (b *Board) HaveCommonSubgrid(x1, y1, x2, y2 int) bool {
 	return gridBeginY1 == gridBeginY2
 }
 
+// HaveCommonUnit returns true if both fields are in the same row, column,
+// subgrid, diagonal (if diagonal constraint is enabled) or cage, i.e. they are neighbours
+// (see ForEachNeighbour) or it is the same field.
+func (b *Board) HaveCommonUnit(x1, y1, x2, y2 int) bool {
+	return x1 == x2 || y1 == y2 || b.HaveCommonSubgrid(x1, y1, x2, y2) ||
+		b.haveCommonDiagonal(x1, y1, x2, y2) || b.haveCommonCage(x1, y1, x2, y2)
+}
+
 func (b *Board) Serialize(writer io.Writer) error {
-	if _, err := io.WriteString(writer, fmt.Sprintf("%d %d\n", b.subgridWidth, b.subgridHeight)); err != nil {
+	if b.regions != nil {
+		return b.serializeJigsaw(writer)
+	}
+	if _, err := io.WriteString(writer, fmt.Sprintf("%d %d%s%s\n", b.subgridWidth, b.subgridHeight, b.diagonalHeaderSuffix(), b.alphabetHeaderSuffix())); err != nil {
 		return err
 	}
 	if _, err := io.WriteString(writer, b.String()); err != nil {
 		return err
 	}
-	return nil
+	return b.serializeCages(writer)
 }
 
 // String writes board in "ASCII art", for example:
@@ -299,9 +580,14 @@ func (b *Board) Serialize(writer io.Writer) error {
 // | 0 0 0 | 0 0 0 |
 // | 6 5 4 | 3 2 1 |
 // +-------+-------+
+// Jigsaw boards are drawn with borders between fields from different regions (see NewJigsaw).
+// Numbers are written with symbols of the board alphabet if it is set (see SetAlphabet).
 func (b *Board) String() string {
+	if b.regions != nil {
+		return b.jigsawString()
+	}
 	var s strings.Builder
-	digitLen := len(fmt.Sprint(b.gridSize))
+	digitLen := b.numberWidth()
 	charsPerSubgridX := b.subgridWidth + 1 + b.subgridWidth*digitLen
 	s.Grow((b.gridSize + b.subgridsCountY + 1) * (charsPerSubgridX*b.subgridsCountX + b.subgridsCountX + 2))
 	dataIndex := 0
@@ -313,7 +599,7 @@ func (b *Board) String() string {
 			if x%b.subgridWidth == 0 {
 				s.WriteString("| ")
 			}
-			s.WriteString(fmt.Sprintf("%*d ", digitLen, b.data[dataIndex]))
+			s.WriteString(b.formatNumber(b.data[dataIndex], digitLen) + " ")
 			dataIndex++
 		}
 		s.WriteString("|\n")
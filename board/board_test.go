@@ -71,6 +71,21 @@ func TestBoardHaveCommonSubgrid(t *testing.T) {
 	assert.False(t, board.HaveCommonSubgrid(0, 0, 3, 0))
 }
 
+func TestBoardUnits(t *testing.T) {
+	b, err := board.New(3, 2)
+	require.NoError(t, err)
+	units := b.Units()
+	require.Len(t, units, 18)
+	for i, unit := range units {
+		assert.Len(t, unit.Fields, 6)
+		assert.Equal(t, board.UnitKind(i/6), unit.Kind)
+		assert.Equal(t, i%6, unit.Index)
+	}
+	assert.Equal(t, []board.Field{{0, 1}, {1, 1}, {2, 1}, {3, 1}, {4, 1}, {5, 1}}, units[1].Fields)
+	assert.Equal(t, []board.Field{{1, 0}, {1, 1}, {1, 2}, {1, 3}, {1, 4}, {1, 5}}, units[7].Fields)
+	assert.Equal(t, []board.Field{{3, 2}, {4, 2}, {5, 2}, {3, 3}, {4, 3}, {5, 3}}, units[15].Fields)
+}
+
 func TestBoardNewFromSerializedFormat(t *testing.T) {
 	t.Run("board can be recreated from string", func(t *testing.T) {
 		board1, err := board.New(3, 2)
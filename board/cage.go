@@ -0,0 +1,184 @@
+package board
+
+import (
+	"fmt"
+	"io"
+	"strings"
+)
+
+const cagesKeyword = "cages"
+
+// Cage is a group of fields used in Killer Sudoku - numbers placed in the cage
+// must add up to Sum and no number can be repeated inside the cage.
+type Cage struct {
+	Fields []Field
+	Sum    int
+}
+
+func (c Cage) String() string {
+	fields := make([]string, 0, len(c.Fields))
+	for _, f := range c.Fields {
+		fields = append(fields, fmt.Sprintf("(%d, %d)", f.X, f.Y))
+	}
+	return fmt.Sprintf("%d: %s", c.Sum, strings.Join(fields, " "))
+}
+
+// AddCage adds Killer Sudoku cage to the board. Fields of the cage must not
+// be repeated and each field can belong to at most one cage. Sum must be possible
+// to achieve with distinct numbers, for example cage with 2 fields on 9x9 board
+// can have sum from 3 (1+2) to 17 (8+9). Cage fields are neighbours (see ForEachNeighbour),
+// but cages are not units (see Units), because they do not have to contain all numbers.
+func (b *Board) AddCage(sum int, fields []Field) error {
+	if len(fields) == 0 || len(fields) > b.gridSize {
+		return fmt.Errorf("invalid cage size, it must be from 1 to %d, got %d", b.gridSize, len(fields))
+	}
+	minSum, maxSum := 0, 0
+	for i := 1; i <= len(fields); i++ {
+		minSum += i
+		maxSum += b.gridSize + 1 - i
+	}
+	if sum < minSum || sum > maxSum {
+		return fmt.Errorf("invalid cage sum %d, for %d fields it must be from %d to %d", sum, len(fields), minSum, maxSum)
+	}
+	cageIndex := len(b.cages)
+	cageOfField := b.cageOfField
+	if cageOfField == nil {
+		cageOfField = make([]int, b.gridSize*b.gridSize)
+		for i := range cageOfField {
+			cageOfField[i] = -1
+		}
+	}
+	for i, f := range fields {
+		if f.X < 0 || f.X >= b.gridSize || f.Y < 0 || f.Y >= b.gridSize {
+			return fmt.Errorf("cage field (%d, %d) is outside of the board", f.X, f.Y)
+		}
+		offset := f.Y*b.gridSize + f.X
+		if cageOfField[offset] >= 0 {
+			return fmt.Errorf("field (%d, %d) already belongs to cage %d", f.X, f.Y, cageOfField[offset])
+		}
+		for _, previous := range fields[:i] {
+			if previous == f {
+				return fmt.Errorf("field (%d, %d) is repeated in the cage", f.X, f.Y)
+			}
+		}
+	}
+	for _, f := range fields {
+		cageOfField[f.Y*b.gridSize+f.X] = cageIndex
+	}
+	b.cageOfField = cageOfField
+	b.cages = append(b.cages, Cage{Fields: append([]Field(nil), fields...), Sum: sum})
+	return nil
+}
+
+// Cages returns all cages added with AddCage. The returned slice must not be modified.
+func (b *Board) Cages() []Cage {
+	return b.cages
+}
+
+// CageIndex returns index of the cage (in Cages) containing field x, y,
+// or -1 if the field does not belong to any cage.
+func (b *Board) CageIndex(x, y int) int {
+	if b.cageOfField == nil {
+		return -1
+	}
+	return b.cageOfField[y*b.gridSize+x]
+}
+
+// haveCommonCage returns true if both fields belong to the same cage.
+func (b *Board) haveCommonCage(x1, y1, x2, y2 int) bool {
+	cageIndex := b.CageIndex(x1, y1)
+	return cageIndex >= 0 && cageIndex == b.CageIndex(x2, y2)
+}
+
+// cageSumBroken returns true if numbers in the cage add up to more than the cage sum
+// or if the cage is full and its numbers do not add up to the sum.
+func (b *Board) cageSumBroken(cage Cage) bool {
+	sum := 0
+	full := true
+	for _, f := range cage.Fields {
+		n := b.Get(f.X, f.Y)
+		sum += int(n)
+		full = full && n != 0
+	}
+	return sum > cage.Sum || (full && sum != cage.Sum)
+}
+
+// forEachCageNeighbour is a part of ForEachNeighbour - it calls operation for fields
+// in the same cage as x0, y0, which have not been visited as neighbours from
+// the same row, column, subgrid or diagonal.
+func (b *Board) forEachCageNeighbour(x0, y0 int, operation func(x, y int)) {
+	cageIndex := b.CageIndex(x0, y0)
+	if cageIndex < 0 {
+		return
+	}
+	for _, f := range b.cages[cageIndex].Fields {
+		if f.X == x0 || f.Y == y0 || b.HaveCommonSubgrid(x0, y0, f.X, f.Y) || b.haveCommonDiagonal(x0, y0, f.X, f.Y) {
+			continue
+		}
+		operation(f.X, f.Y)
+	}
+}
+
+// scanCages reads the optional cages section, which can follow the last section of
+// serialized board (name and gridSize describe that section for error reporting). Section starts
+// with line containing word "cages", each following line with numbers is a cage - the first number
+// is cage sum, the rest are x, y coordinates of cage fields (format produced by Cage.String), e.g.:
+// cages:
+// 3: (0, 0) (1, 0)
+// 10: (2, 0) (2, 1) (3, 1)
+// It returns error if there are any lines with numbers before cages section.
+func (s *numberLinesScanner) scanCages(name string, gridSize int) ([]Cage, error) {
+	var cages []Cage
+	cagesSection := false
+	for s.scanner.Scan() {
+		s.lineNumber++
+		if !cagesSection && strings.Contains(s.scanner.Text(), cagesKeyword) {
+			cagesSection = true
+			continue
+		}
+		numbers, err := s.parseNumbers()
+		if err != nil {
+			return nil, err
+		}
+		if len(numbers) == 0 {
+			continue
+		}
+		if !cagesSection {
+			return nil, fmt.Errorf("too many %s lines, expected %d", name, gridSize)
+		}
+		if len(numbers)%2 != 1 || len(numbers) < 3 {
+			return nil, fmt.Errorf("expected cage sum and field coordinates in line %d: %s", s.lineNumber, s.scanner.Text())
+		}
+		cage := Cage{Sum: numbers[0]}
+		for i := 1; i < len(numbers); i += 2 {
+			cage.Fields = append(cage.Fields, Field{numbers[i], numbers[i+1]})
+		}
+		cages = append(cages, cage)
+	}
+	return cages, nil
+}
+
+// addCages adds cages read by scanCages to the board.
+func (b *Board) addCages(cages []Cage) error {
+	for i, cage := range cages {
+		if err := b.AddCage(cage.Sum, cage.Fields); err != nil {
+			return fmt.Errorf("error adding cage %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// serializeCages writes cages section (see scanCages) if the board has any cages.
+func (b *Board) serializeCages(writer io.Writer) error {
+	if len(b.cages) == 0 {
+		return nil
+	}
+	var s strings.Builder
+	s.WriteString(cagesKeyword + ":\n")
+	for _, cage := range b.cages {
+		s.WriteString(cage.String())
+		s.WriteString("\n")
+	}
+	_, err := io.WriteString(writer, s.String())
+	return err
+}
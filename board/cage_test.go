@@ -0,0 +1,143 @@
+package board_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tomaszmj/sudoku/board"
+)
+
+func TestAddCage(t *testing.T) {
+	b, err := board.New(2, 2)
+	require.NoError(t, err)
+	require.NoError(t, b.AddCage(3, []board.Field{{0, 0}, {1, 0}}))
+	require.NoError(t, b.AddCage(9, []board.Field{{2, 0}, {2, 1}, {3, 1}}))
+	assert.Equal(t, []board.Cage{
+		{Fields: []board.Field{{0, 0}, {1, 0}}, Sum: 3},
+		{Fields: []board.Field{{2, 0}, {2, 1}, {3, 1}}, Sum: 9},
+	}, b.Cages())
+	assert.Equal(t, 0, b.CageIndex(1, 0))
+	assert.Equal(t, 1, b.CageIndex(3, 1))
+	assert.Equal(t, -1, b.CageIndex(3, 3))
+
+	t.Run("empty cage", func(t *testing.T) {
+		assert.Error(t, b.AddCage(1, nil))
+	})
+	t.Run("too many fields", func(t *testing.T) {
+		assert.Error(t, b.AddCage(15, []board.Field{{0, 2}, {1, 2}, {2, 2}, {3, 2}, {0, 3}}))
+	})
+	t.Run("sum too small", func(t *testing.T) {
+		assert.Error(t, b.AddCage(2, []board.Field{{0, 2}, {1, 2}}))
+	})
+	t.Run("sum too big", func(t *testing.T) {
+		assert.Error(t, b.AddCage(8, []board.Field{{0, 2}, {1, 2}}))
+	})
+	t.Run("field outside of the board", func(t *testing.T) {
+		assert.Error(t, b.AddCage(3, []board.Field{{0, 2}, {4, 2}}))
+	})
+	t.Run("repeated field", func(t *testing.T) {
+		assert.Error(t, b.AddCage(3, []board.Field{{0, 2}, {0, 2}}))
+	})
+	t.Run("field already in cage", func(t *testing.T) {
+		assert.Error(t, b.AddCage(3, []board.Field{{0, 2}, {1, 0}}))
+	})
+	assert.Len(t, b.Cages(), 2)
+	assert.Equal(t, -1, b.CageIndex(0, 2))
+}
+
+func TestCageNeighbours(t *testing.T) {
+	b, err := board.New(3, 3)
+	require.NoError(t, err)
+	require.NoError(t, b.AddCage(10, []board.Field{{2, 2}, {3, 2}, {3, 3}}))
+	neighbours := make(map[board.Field]int)
+	b.ForEachNeighbour(2, 2, func(x, y int) {
+		neighbours[board.Field{X: x, Y: y}]++
+	})
+	assert.Len(t, neighbours, 21)
+	assert.Equal(t, 1, neighbours[board.Field{X: 3, Y: 2}])
+	assert.Equal(t, 1, neighbours[board.Field{X: 3, Y: 3}])
+	assert.True(t, b.HaveCommonUnit(2, 2, 3, 3))
+	assert.False(t, b.HaveCommonUnit(2, 2, 4, 4))
+}
+
+func TestCageRules(t *testing.T) {
+	b, err := board.New(2, 2)
+	require.NoError(t, err)
+	require.NoError(t, b.AddCage(6, []board.Field{{1, 1}, {2, 1}, {2, 2}}))
+	b.Set(1, 1, 1)
+	b.Set(2, 1, 3)
+	assert.NoError(t, b.CheckRules())
+
+	b.Set(2, 2, 3)
+	var violations board.Violations
+	require.ErrorAs(t, b.CheckRules(), &violations)
+	assert.Equal(t, board.Violations{
+		{Unit: board.Column, UnitIndex: 2, Number: 3, Fields: []board.Field{{2, 1}, {2, 2}}},
+		{Unit: board.KillerCage, UnitIndex: 0, Number: 3, Fields: []board.Field{{2, 1}, {2, 2}}},
+		{Unit: board.KillerCage, UnitIndex: 0, Fields: []board.Field{{1, 1}, {2, 1}, {2, 2}}, Sum: 6},
+	}, violations)
+	assert.Equal(t, "numbers in cage 0 at (1, 1), (2, 1), (2, 2) do not add up to 6", violations[2].Error())
+
+	b.Set(2, 2, 0)
+	b.Set(1, 1, 3)
+	b.Set(2, 1, 4) // partial sum greater than cage sum
+	require.ErrorAs(t, b.CheckRules(), &violations)
+	assert.Len(t, violations, 1)
+}
+
+func TestCageSerialization(t *testing.T) {
+	t.Run("board can be recreated from string", func(t *testing.T) {
+		board1, err := board.New(2, 2)
+		require.NoError(t, err)
+		board1.Set(0, 0, 1)
+		require.NoError(t, board1.AddCage(3, []board.Field{{0, 0}, {1, 0}}))
+		require.NoError(t, board1.AddCage(4, []board.Field{{3, 3}}))
+		var serializeOutput strings.Builder
+		require.NoError(t, board1.Serialize(&serializeOutput))
+		assert.True(t, strings.HasSuffix(serializeOutput.String(), "cages:\n3: (0, 0) (1, 0)\n4: (3, 3)\n"))
+		board2, err := board.NewFromSerializedFormat(strings.NewReader(serializeOutput.String()))
+		require.NoError(t, err)
+		assert.Equal(t, board1, board2)
+		assert.True(t, board1.Equal(board2))
+	})
+	t.Run("jigsaw board can be recreated from string", func(t *testing.T) {
+		board1, err := board.NewJigsaw(jigsawRegionMap)
+		require.NoError(t, err)
+		require.NoError(t, board1.AddCage(7, []board.Field{{0, 0}, {1, 0}, {1, 1}}))
+		var serializeOutput strings.Builder
+		require.NoError(t, board1.Serialize(&serializeOutput))
+		board2, err := board.NewFromSerializedFormat(strings.NewReader(serializeOutput.String()))
+		require.NoError(t, err)
+		assert.Equal(t, board1, board2)
+	})
+	t.Run("copy is independent", func(t *testing.T) {
+		board1, err := board.New(2, 2)
+		require.NoError(t, err)
+		require.NoError(t, board1.AddCage(3, []board.Field{{0, 0}, {1, 0}}))
+		board2 := board1.Copy()
+		assert.True(t, board1.Equal(board2))
+		require.NoError(t, board2.AddCage(3, []board.Field{{0, 1}, {1, 1}}))
+		assert.False(t, board1.Equal(board2))
+		assert.Len(t, board1.Cages(), 1)
+		assert.Equal(t, -1, board1.CageIndex(0, 1))
+	})
+	t.Run("tolerant format", func(t *testing.T) {
+		b, err := board.NewFromSerializedFormat(strings.NewReader("1 2\n0 0\n0 0\n\nkiller cages\nsum 3 - 0,0 1,0\n"))
+		require.NoError(t, err)
+		assert.Equal(t, []board.Cage{{Fields: []board.Field{{0, 0}, {1, 0}}, Sum: 3}}, b.Cages())
+	})
+	t.Run("numbers before cages section", func(t *testing.T) {
+		_, err := board.NewFromSerializedFormat(strings.NewReader("1 2\n0 0\n0 0\n3: (0, 0) (1, 0)\n"))
+		assert.Error(t, err)
+	})
+	t.Run("missing field coordinate", func(t *testing.T) {
+		_, err := board.NewFromSerializedFormat(strings.NewReader("1 2\n0 0\n0 0\ncages:\n3: (0, 0) (1)\n"))
+		assert.Error(t, err)
+	})
+	t.Run("invalid cage", func(t *testing.T) {
+		_, err := board.NewFromSerializedFormat(strings.NewReader("1 2\n0 0\n0 0\ncages:\n4: (0, 0) (1, 0)\n"))
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,321 @@
+package board
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/tomaszmj/sudoku/set"
+)
+
+const candidatesKeyword = "candidates"
+
+// Candidates (pencil marks) hold set of numbers that can be placed on each field of a board.
+// Filled fields have no candidates. Candidates are not bound to the board they were computed from -
+// methods that need to know numbers on the board or its units take it as an argument.
+type Candidates struct {
+	gridSize int
+	// subgridWidth and subgridHeight are used only by String (to draw subgrid borders),
+	// they are 0 for jigsaw boards
+	subgridWidth  int
+	subgridHeight int
+	sets          []*set.Set
+}
+
+// NewCandidates computes candidates of each empty field of the board - numbers which
+// are not present in any of its neighbours (see ForEachNeighbour) and are not removed by
+// constraints (see CandidatesRemover). Killer Sudoku cage sums are not taken into account.
+func NewCandidates(b *Board) *Candidates {
+	c := &Candidates{
+		gridSize:      b.gridSize,
+		subgridWidth:  b.subgridWidth,
+		subgridHeight: b.subgridHeight,
+		sets:          make([]*set.Set, b.gridSize*b.gridSize),
+	}
+	for y := 0; y < b.gridSize; y++ {
+		for x := 0; x < b.gridSize; x++ {
+			c.sets[y*b.gridSize+x] = set.New(b.gridSize)
+			c.compute(b, x, y)
+		}
+	}
+	return c
+}
+
+// compute sets candidates of field x, y from scratch.
+func (c *Candidates) compute(b *Board, x, y int) {
+	candidates := c.sets[y*c.gridSize+x]
+	candidates.Clear()
+	if b.Get(x, y) != 0 {
+		return
+	}
+	candidates.Fill()
+	b.ForEachNeighbour(x, y, func(x, y int) {
+		if n := b.Get(x, y); n != 0 {
+			candidates.Remove(int(n))
+		}
+	})
+	b.RemoveCandidates(x, y, candidates)
+}
+
+// Size returns total width/height of the board, for which candidates have been computed.
+func (c *Candidates) Size() int {
+	return c.gridSize
+}
+
+// Get returns candidates of field x, y. The returned set must not be modified,
+// use Eliminate to remove candidates.
+func (c *Candidates) Get(x, y int) *set.Set {
+	return c.sets[y*c.gridSize+x]
+}
+
+// Has returns true if n is a candidate of field x, y.
+func (c *Candidates) Has(x, y int, n uint16) bool {
+	return c.sets[y*c.gridSize+x].Get(int(n))
+}
+
+// Eliminate removes candidate n from field x, y. It returns false if n was not a candidate.
+func (c *Candidates) Eliminate(x, y int, n uint16) bool {
+	return c.sets[y*c.gridSize+x].Remove(int(n))
+}
+
+// Place puts number n on field x, y of the board and updates candidates - the field has
+// no candidates anymore and n is eliminated from its neighbours. Fields sharing
+// a constraint with x, y can have other candidates eliminated (see CandidatesRemover).
+// Candidates eliminated before (with Eliminate) remain eliminated.
+func (c *Candidates) Place(b *Board, x, y int, n uint16) {
+	b.Set(x, y, n)
+	c.sets[y*c.gridSize+x].Clear()
+	b.ForEachNeighbour(x, y, func(x, y int) {
+		c.sets[y*c.gridSize+x].Remove(int(n))
+	})
+	c.forEachFieldWithCommonConstraint(b, x, y, func(x, y int) {
+		if b.Get(x, y) == 0 {
+			b.RemoveCandidates(x, y, c.sets[y*c.gridSize+x])
+		}
+	})
+}
+
+// Clear removes number from field x, y of the board and updates candidates. Candidates of
+// the field, its neighbours and fields sharing a constraint with it are computed from scratch
+// (as in NewCandidates), so candidates eliminated before from these fields are restored.
+func (c *Candidates) Clear(b *Board, x, y int) {
+	b.Set(x, y, 0)
+	c.compute(b, x, y)
+	b.ForEachNeighbour(x, y, func(x, y int) {
+		c.compute(b, x, y)
+	})
+	c.forEachFieldWithCommonConstraint(b, x, y, func(x, y int) {
+		c.compute(b, x, y)
+	})
+}
+
+// forEachFieldWithCommonConstraint calls operation for each field (other than x0, y0)
+// covered by any constraint covering x0, y0. Fields can be visited more than once.
+func (c *Candidates) forEachFieldWithCommonConstraint(b *Board, x0, y0 int, operation func(x, y int)) {
+	b.ForEachConstraint(x0, y0, func(constraint Constraint) {
+		for _, f := range constraint.Fields() {
+			if f.X != x0 || f.Y != y0 {
+				operation(f.X, f.Y)
+			}
+		}
+	})
+}
+
+func (c *Candidates) Copy() *Candidates {
+	sets := make([]*set.Set, len(c.sets))
+	for i, s := range c.sets {
+		sets[i] = s.Copy()
+	}
+	return &Candidates{
+		gridSize:      c.gridSize,
+		subgridWidth:  c.subgridWidth,
+		subgridHeight: c.subgridHeight,
+		sets:          sets,
+	}
+}
+
+// Equal returns true if both have the same candidates on every field (subgrid sizes are ignored).
+func (c *Candidates) Equal(c2 *Candidates) bool {
+	if c.gridSize != c2.gridSize {
+		return false
+	}
+	for i, s := range c.sets {
+		if !s.Equal(c2.sets[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// String writes candidates in pencil-mark grid format, in which each field has all its
+// candidates written without spaces (or separated by commas, if board size is greater than 9)
+// and fields without candidates are written as dots. Columns are aligned to the longest
+// field in each column. For example, for 4x4 board with 2x2 subgrids:
+// +----------+---------+
+// | .   234  | 234 34  |
+// | 234 234  | .   34  |
+// +----------+---------+
+// | 234 1234 | 34  134 |
+// | 34  134  | 34  .   |
+// +----------+---------+
+func (c *Candidates) String() string {
+	fields := make([]string, len(c.sets))
+	widths := make([]int, c.gridSize)
+	for i, s := range c.sets {
+		fields[i] = c.fieldString(s)
+		if x := i % c.gridSize; len(fields[i]) > widths[x] {
+			widths[x] = len(fields[i])
+		}
+	}
+	subgridWidth, subgridHeight := c.subgridWidth, c.subgridHeight
+	if subgridWidth == 0 { // jigsaw - only outer border is drawn
+		subgridWidth, subgridHeight = c.gridSize, c.gridSize
+	}
+	var line strings.Builder
+	for x, width := range widths {
+		if x%subgridWidth == 0 {
+			line.WriteString("+-")
+		}
+		line.WriteString(strings.Repeat("-", width+1))
+	}
+	line.WriteString("+\n")
+	var s strings.Builder
+	for y := 0; y < c.gridSize; y++ {
+		if y%subgridHeight == 0 {
+			s.WriteString(line.String())
+		}
+		for x := 0; x < c.gridSize; x++ {
+			if x%subgridWidth == 0 {
+				s.WriteString("| ")
+			}
+			s.WriteString(fmt.Sprintf("%-*s ", widths[x], fields[y*c.gridSize+x]))
+		}
+		s.WriteString("|\n")
+	}
+	s.WriteString(line.String())
+	return s.String()
+}
+
+func (c *Candidates) fieldString(candidates *set.Set) string {
+	if candidates.Len() == 0 {
+		return "."
+	}
+	separator := ""
+	if c.gridSize > 9 {
+		separator = ","
+	}
+	numbers := make([]string, 0, candidates.Len())
+	candidates.ForEach(func(n int) bool {
+		numbers = append(numbers, strconv.Itoa(n))
+		return false
+	})
+	return strings.Join(numbers, separator)
+}
+
+// Serialize writes candidates in format accepted by NewCandidatesFromSerializedFormat.
+func (c *Candidates) Serialize(writer io.Writer) error {
+	header := fmt.Sprintf("%s %d %d\n", candidatesKeyword, c.subgridWidth, c.subgridHeight)
+	if c.subgridWidth == 0 {
+		header = fmt.Sprintf("%s %d\n", candidatesKeyword, c.gridSize)
+	}
+	if _, err := io.WriteString(writer, header); err != nil {
+		return err
+	}
+	_, err := io.WriteString(writer, c.String())
+	return err
+}
+
+var candidatesFieldRegex = regexp.MustCompile(`[\d,]+|\.`)
+
+// NewCandidatesFromSerializedFormat creates candidates from format produced by Serialize.
+// The first line is "candidates" followed by subgrid width and height (or only board size
+// for jigsaw boards), following lines are pencil-mark grid in format described in String.
+// As in NewFromSerializedFormat, lines without fields (digits or dots) are ignored,
+// but there must be no fields after the last line of the grid. For example:
+// candidates 2 1
+// 1  12
+// 12 .
+func NewCandidatesFromSerializedFormat(reader io.Reader) (*Candidates, error) {
+	scanner := bufio.NewScanner(reader)
+	if !scanner.Scan() {
+		return nil, fmt.Errorf("error - no data")
+	}
+	if !strings.HasPrefix(scanner.Text(), candidatesKeyword) {
+		return nil, fmt.Errorf("error parsing - expected %q keyword, line: %s", candidatesKeyword, scanner.Text())
+	}
+	header := findNumbersRegex.FindAll(scanner.Bytes(), 3)
+	if len(header) != 1 && len(header) != 2 {
+		return nil, fmt.Errorf("error parsing - expected 1 or 2 numbers, line: %s", scanner.Text())
+	}
+	sizes := make([]int, 0, len(header))
+	for _, numberBytes := range header {
+		number, err := strconv.Atoi(string(numberBytes))
+		if err != nil {
+			return nil, fmt.Errorf("error parsing number %w in line: %s", err, scanner.Text())
+		}
+		sizes = append(sizes, number)
+	}
+	c := &Candidates{gridSize: sizes[0]}
+	if len(sizes) == 2 {
+		c.subgridWidth, c.subgridHeight = sizes[0], sizes[1]
+		c.gridSize = sizes[0] * sizes[1]
+	}
+	if c.gridSize < 1 || c.gridSize > MaxSize {
+		return nil, fmt.Errorf("invalid grid size, it must be from 1 to %d, got %d", MaxSize, c.gridSize)
+	}
+	lineNumber := 1
+	for scanner.Scan() {
+		lineNumber++
+		fields := candidatesFieldRegex.FindAllString(scanner.Text(), -1)
+		if len(fields) == 0 {
+			continue
+		}
+		if len(c.sets) == c.gridSize*c.gridSize {
+			return nil, fmt.Errorf("unexpected data after candidates in line %d: %s", lineNumber, scanner.Text())
+		}
+		if len(fields) != c.gridSize {
+			return nil, fmt.Errorf("expected %d fields, got %d in line %d: %s", c.gridSize, len(fields), lineNumber, scanner.Text())
+		}
+		for _, field := range fields {
+			candidates, err := c.parseField(field)
+			if err != nil {
+				return nil, fmt.Errorf("%w in line %d: %s", err, lineNumber, scanner.Text())
+			}
+			c.sets = append(c.sets, candidates)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(c.sets) != c.gridSize*c.gridSize {
+		return nil, fmt.Errorf("invalid number of candidates lines, expected %d, got %d", c.gridSize, len(c.sets)/c.gridSize)
+	}
+	return c, nil
+}
+
+func (c *Candidates) parseField(field string) (*set.Set, error) {
+	candidates := set.New(c.gridSize)
+	if field == "." {
+		return candidates, nil
+	}
+	var numbers []string
+	if c.gridSize > 9 {
+		numbers = strings.Split(field, ",")
+	} else {
+		numbers = strings.Split(field, "")
+	}
+	for _, numberString := range numbers {
+		number, err := strconv.Atoi(numberString)
+		if err != nil {
+			return nil, fmt.Errorf("error parsing candidate %q", numberString)
+		}
+		if number < 1 || number > c.gridSize {
+			return nil, fmt.Errorf("invalid candidate %d", number)
+		}
+		candidates.Add(number)
+	}
+	return candidates, nil
+}
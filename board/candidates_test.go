@@ -0,0 +1,119 @@
+package board_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tomaszmj/sudoku/board"
+)
+
+const candidatesTestBoard = `2 2
+1 0 0 0
+0 0 1 0
+0 0 0 0
+0 0 0 2
+`
+
+const candidatesTestString = `+----------+---------+
+| .   234  | 234 34  |
+| 234 234  | .   34  |
++----------+---------+
+| 234 1234 | 34  134 |
+| 34  134  | 34  .   |
++----------+---------+
+`
+
+func TestNewCandidates(t *testing.T) {
+	b, err := board.NewFromSerializedFormat(strings.NewReader(candidatesTestBoard))
+	require.NoError(t, err)
+	c := board.NewCandidates(b)
+	assert.Equal(t, 4, c.Size())
+	assert.Equal(t, 0, c.Get(0, 0).Len())
+	assert.Equal(t, "{1,3,4,}", c.Get(3, 2).String())
+	assert.True(t, c.Has(1, 2, 1))
+	assert.False(t, c.Has(0, 1, 1))
+	assert.Equal(t, candidatesTestString, c.String())
+}
+
+func TestCandidatesPlaceAndClear(t *testing.T) {
+	b, err := board.NewFromSerializedFormat(strings.NewReader(candidatesTestBoard))
+	require.NoError(t, err)
+	c := board.NewCandidates(b)
+	assert.True(t, c.Eliminate(1, 3, 4))
+	assert.False(t, c.Eliminate(1, 3, 4))
+
+	c.Place(b, 1, 2, 1)
+	assert.Equal(t, uint16(1), b.Get(1, 2))
+	assert.Equal(t, 0, c.Get(1, 2).Len())
+	assert.Equal(t, "{3,}", c.Get(1, 3).String())
+	assert.Equal(t, "{3,4,}", c.Get(3, 2).String())
+	assert.Equal(t, "{2,3,4,}", c.Get(0, 1).String())
+
+	c.Clear(b, 1, 2)
+	assert.Equal(t, uint16(0), b.Get(1, 2))
+	assert.True(t, c.Equal(board.NewCandidates(b)), "eliminated candidates of neighbours are restored")
+}
+
+func TestCandidatesWithConstraint(t *testing.T) {
+	b, err := board.New(2, 2)
+	require.NoError(t, err)
+	require.NoError(t, b.AddConstraint(board.Thermometer{{0, 0}, {1, 0}, {2, 0}}))
+	c := board.NewCandidates(b)
+	assert.Equal(t, "{1,2,}", c.Get(0, 0).String())
+	assert.Equal(t, "{3,4,}", c.Get(2, 0).String())
+
+	c.Place(b, 1, 0, 3)
+	assert.Equal(t, "{1,2,}", c.Get(0, 0).String())
+	assert.Equal(t, "{4,}", c.Get(2, 0).String())
+
+	c.Clear(b, 1, 0)
+	assert.Equal(t, "{3,4,}", c.Get(2, 0).String())
+}
+
+func TestCandidatesSerialization(t *testing.T) {
+	b, err := board.NewFromSerializedFormat(strings.NewReader(candidatesTestBoard))
+	require.NoError(t, err)
+	c := board.NewCandidates(b)
+	var s strings.Builder
+	require.NoError(t, c.Serialize(&s))
+	assert.Equal(t, "candidates 2 2\n"+candidatesTestString, s.String())
+	c2, err := board.NewCandidatesFromSerializedFormat(strings.NewReader(s.String()))
+	require.NoError(t, err)
+	assert.True(t, c.Equal(c2))
+	assert.Equal(t, c.String(), c2.String())
+
+	c3, err := board.NewCandidatesFromSerializedFormat(strings.NewReader("candidates 2\n1  12\n\n12 .\n"))
+	require.NoError(t, err)
+	assert.Equal(t, "{1,2,}", c3.Get(1, 0).String())
+	assert.Equal(t, 0, c3.Get(1, 1).Len())
+	assert.Equal(t, "+-------+\n| 1  12 |\n| 12 .  |\n+-------+\n", c3.String())
+
+	big, err := board.New(4, 3)
+	require.NoError(t, err)
+	big.Set(0, 0, 11)
+	c4 := board.NewCandidates(big)
+	s.Reset()
+	require.NoError(t, c4.Serialize(&s))
+	assert.True(t, strings.HasPrefix(s.String(), "candidates 4 3\n"))
+	assert.Contains(t, s.String(), "| .                       1,2,3,4,5,6,7,8,9,10,12 ")
+	c5, err := board.NewCandidatesFromSerializedFormat(strings.NewReader(s.String()))
+	require.NoError(t, err)
+	assert.True(t, c4.Equal(c5))
+
+	for _, invalid := range []string{
+		"",
+		"2 2\n",
+		"candidates\n",
+		"candidates 2\n1 12\n",
+		"candidates 2\n1 12\n12\n",
+		"candidates 2\n1 13\n12 .\n",
+		"candidates 2\n1 1,2\n12 .\n",
+		"candidates 2\n1 12\n12 .\n1 2\n",
+		"candidates 2\n1 12\n12 .\n\n.\n",
+	} {
+		_, err := board.NewCandidatesFromSerializedFormat(strings.NewReader(invalid))
+		assert.Error(t, err, invalid)
+	}
+}
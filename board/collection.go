@@ -0,0 +1,168 @@
+package board
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// metadataPrefix starts lines with metadata or comments in collections (see CollectionReader).
+const metadataPrefix = "#"
+
+// CollectionReader reads many boards (records) from one stream. Each record is either:
+// - one line in one-line format (see NewFromLine), so collection can have one board per line,
+// - or block of lines in serialized format (see NewFromSerializedFormat), ended with blank line,
+// metadata comment or end of input.
+// Lines starting with "#" are comments. Comments in format "# key: value" set metadata
+// of the next record (see Metadata.SetValue), for example:
+// # name: puzzle 1
+// # difficulty: easy
+// 4.....8.5.3..........7......2.....6.....8.4......1.......6.3.7.5..2.....1.4......
+// # name: puzzle 2
+// 2 2
+// 0 0 0 3
+// 0 1 0 4
+// 4 2 3 1
+// 1 3 4 2
+//
+// Multi-grid puzzles are not supported.
+type CollectionReader struct {
+	scanner    *bufio.Scanner
+	lineNumber int
+	metadata   Metadata // metadata of the next record, read when looking for the end of the previous one
+	done       bool
+}
+
+// RecordError is returned by CollectionReader.Next if a record is invalid.
+// Reading can be continued with the next record.
+type RecordError struct {
+	Line     int // number of the first line of the record
+	Metadata Metadata
+	Err      error
+}
+
+func (e *RecordError) Error() string {
+	if e.Metadata.Name != "" {
+		return fmt.Sprintf("invalid record %q in line %d: %s", e.Metadata.Name, e.Line, e.Err)
+	}
+	return fmt.Sprintf("invalid record in line %d: %s", e.Line, e.Err)
+}
+
+func (e *RecordError) Unwrap() error {
+	return e.Err
+}
+
+// maxCollectionLineLength is the maximum length of a line read by CollectionReader.
+const maxCollectionLineLength = 1024 * 1024
+
+// NewCollectionReader returns CollectionReader reading boards from reader.
+func NewCollectionReader(reader io.Reader) *CollectionReader {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(nil, maxCollectionLineLength)
+	return &CollectionReader{scanner: scanner}
+}
+
+// Next returns the next board from the collection, with metadata set from comments preceding it.
+// It returns io.EOF if there are no more boards and *RecordError if the record is invalid
+// (then Next can be called again to read the following records). Other errors
+// (returned when reading from the stream fails) end reading.
+func (c *CollectionReader) Next() (*Board, error) {
+	if c.done {
+		return nil, io.EOF
+	}
+	metadata := c.metadata
+	c.metadata = Metadata{}
+	var block []string
+	firstLine := 0
+	for c.scanner.Scan() {
+		c.lineNumber++
+		line := strings.TrimSpace(c.scanner.Text())
+		if strings.HasPrefix(line, metadataPrefix) {
+			if block == nil {
+				parseMetadataLine(line, &metadata)
+			} else if parseMetadataLine(line, &c.metadata) {
+				break
+			}
+			continue
+		}
+		if line == "" {
+			if block != nil {
+				break
+			}
+			continue
+		}
+		if block == nil {
+			firstLine = c.lineNumber
+			if isLine(line) {
+				b, err := NewFromLine(line)
+				return newRecord(b, err, firstLine, metadata)
+			}
+		}
+		block = append(block, line)
+	}
+	if err := c.scanner.Err(); err != nil {
+		c.done = true
+		return nil, err
+	}
+	if block == nil {
+		c.done = true // metadata not followed by any record is ignored
+		return nil, io.EOF
+	}
+	b, err := NewFromSerializedFormat(strings.NewReader(strings.Join(block, "\n")))
+	return newRecord(b, err, firstLine, metadata)
+}
+
+// newRecord sets metadata of the board read from record starting in given line,
+// or wraps error returned when reading it into RecordError.
+func newRecord(b *Board, err error, line int, metadata Metadata) (*Board, error) {
+	if err != nil {
+		return nil, &RecordError{Line: line, Metadata: metadata, Err: err}
+	}
+	b.SetMetadata(metadata)
+	return b, nil
+}
+
+// parseMetadataLine sets metadata value and returns true if line is in format "# key: value"
+// (other comment lines are ignored).
+func parseMetadataLine(line string, metadata *Metadata) bool {
+	key, value, ok := cut(strings.TrimPrefix(line, metadataPrefix), ":")
+	key = strings.TrimSpace(key)
+	if !ok || key == "" || strings.ContainsAny(key, " \t") {
+		return false
+	}
+	metadata.SetValue(key, strings.TrimSpace(value))
+	return true
+}
+
+// cut works like strings.Cut (which is not available in Go 1.17).
+func cut(s, sep string) (before, after string, found bool) {
+	if i := strings.Index(s, sep); i >= 0 {
+		return s[:i], s[i+len(sep):], true
+	}
+	return s, "", false
+}
+
+// WriteCollection writes boards in format read by CollectionReader. Each board is preceded
+// by its metadata. Boards which can be written in one-line format (see Line) take one line,
+// the others are serialized (see Serialize) and followed by blank line.
+func WriteCollection(writer io.Writer, boards []*Board) error {
+	for _, b := range boards {
+		if err := b.metadata.serialize(writer); err != nil {
+			return err
+		}
+		if line, err := b.Line(); err == nil {
+			if _, err := io.WriteString(writer, line+"\n"); err != nil {
+				return err
+			}
+			continue
+		}
+		if err := b.Serialize(writer); err != nil {
+			return err
+		}
+		if _, err := io.WriteString(writer, "\n"); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,137 @@
+package board_test
+
+import (
+	"errors"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tomaszmj/sudoku/board"
+)
+
+func readCollection(t *testing.T, r io.Reader) ([]*board.Board, []error) {
+	reader := board.NewCollectionReader(r)
+	var boards []*board.Board
+	var errs []error
+	for {
+		b, err := reader.Next()
+		if err == io.EOF {
+			return boards, errs
+		}
+		if err != nil {
+			var recordErr *board.RecordError
+			require.True(t, errors.As(err, &recordErr), "unexpected error: %s", err)
+			errs = append(errs, err)
+			continue
+		}
+		boards = append(boards, b)
+	}
+}
+
+func TestCollectionReader(t *testing.T) {
+	t.Run("one board per line", func(t *testing.T) {
+		input := "# comment without key\n" +
+			"# name: first\n" +
+			"# Difficulty: hard\n" +
+			line9x9 + "\n" +
+			strings.ReplaceAll(line9x9, ".", "0") + "\n" +
+			"\n" +
+			"# source: somewhere\n" +
+			"# rating: 4.5\n" +
+			"1..4" + strings.Repeat(".", 12) + "\n"
+		boards, errs := readCollection(t, strings.NewReader(input))
+		assert.Empty(t, errs)
+		require.Len(t, boards, 3)
+		assert.Equal(t, board.Metadata{Name: "first", Difficulty: "hard"}, boards[0].Metadata())
+		assert.True(t, boards[1].Metadata().IsEmpty())
+		assert.True(t, boards[0].Equal(boards[1]))
+		assert.Equal(t, board.Metadata{Source: "somewhere", Other: map[string]string{"rating": "4.5"}}, boards[2].Metadata())
+		assert.Equal(t, 4, boards[2].Size())
+	})
+
+	t.Run("boards separated by blank lines", func(t *testing.T) {
+		jigsaw, err := os.ReadFile("../cmd/boards/jigsaw9x9.txt")
+		require.NoError(t, err)
+		input := "# name: 2x2\n2 2\n0 0 0 3\n0 1 0 4\n4 2 3 1\n1 3 4 2\n\n\n" +
+			"# name: jigsaw\n" + string(jigsaw) + "\n" +
+			line9x9 + "\n"
+		boards, errs := readCollection(t, strings.NewReader(input))
+		assert.Empty(t, errs)
+		require.Len(t, boards, 3)
+		assert.Equal(t, "2x2", boards[0].Metadata().Name)
+		assert.Equal(t, uint16(3), boards[0].Get(3, 0))
+		assert.Equal(t, "jigsaw", boards[1].Metadata().Name)
+		assert.True(t, boards[1].IsJigsaw())
+		assert.Equal(t, 9, boards[2].Size())
+	})
+
+	t.Run("invalid records", func(t *testing.T) {
+		input := line9x9 + "\n" +
+			"# name: too short\n" +
+			line9x9[:80] + "\n" +
+			"# name: invalid board\n" +
+			"2 2\n0 0 0 3\n\n" +
+			line9x9 + "\n"
+		boards, errs := readCollection(t, strings.NewReader(input))
+		assert.Len(t, boards, 2)
+		require.Len(t, errs, 2)
+		var recordErr *board.RecordError
+		require.True(t, errors.As(errs[0], &recordErr))
+		assert.Equal(t, 3, recordErr.Line)
+		assert.Equal(t, "too short", recordErr.Metadata.Name)
+		require.True(t, errors.As(errs[1], &recordErr))
+		assert.Equal(t, 5, recordErr.Line)
+		assert.Contains(t, recordErr.Error(), `"invalid board"`)
+	})
+
+	t.Run("empty input", func(t *testing.T) {
+		boards, errs := readCollection(t, strings.NewReader("# nothing here\n\n"))
+		assert.Empty(t, boards)
+		assert.Empty(t, errs)
+	})
+}
+
+func TestWriteCollection(t *testing.T) {
+	b1, err := board.NewFromLine(line9x9)
+	require.NoError(t, err)
+	b1.SetMetadata(board.Metadata{Name: "one-line", Other: map[string]string{"b": "2", "a": "1"}})
+	b2, err := board.New(2, 2)
+	require.NoError(t, err)
+	b2.SetDiagonal(true)
+	b2.SetMetadata(board.Metadata{Name: "diagonal", Difficulty: "easy"})
+	b3, err := board.New(3, 2)
+	require.NoError(t, err)
+	b4, err := board.New(1, 1)
+	require.NoError(t, err)
+	b4.Set(0, 0, 1)
+
+	var s strings.Builder
+	require.NoError(t, board.WriteCollection(&s, []*board.Board{b1, b2, b3, b4}))
+	assert.True(t, strings.HasPrefix(s.String(), "# name: one-line\n# a: 1\n# b: 2\n"+line9x9+"\n# name: diagonal\n# difficulty: easy\n2 2 diagonal\n"))
+	assert.True(t, strings.HasSuffix(s.String(), "\n1x1:1\n"))
+
+	boards, errs := readCollection(t, strings.NewReader(s.String()))
+	assert.Empty(t, errs)
+	require.Len(t, boards, 4)
+	for i, b := range []*board.Board{b1, b2, b3, b4} {
+		assert.True(t, b.Equal(boards[i]))
+		assert.Equal(t, b.Metadata(), boards[i].Metadata())
+	}
+}
+
+func TestMetadata(t *testing.T) {
+	b, err := board.New(2, 2)
+	require.NoError(t, err)
+	b.SetMetadata(board.Metadata{Name: "a", Other: map[string]string{"key": "value"}})
+	c := b.Copy()
+	assert.Equal(t, b.Metadata(), c.Metadata())
+	c.Metadata().Other["key"] = "changed"
+	assert.Equal(t, "value", b.Metadata().Other["key"])
+
+	// metadata is ignored when boards are compared
+	c.SetMetadata(board.Metadata{})
+	assert.True(t, b.Equal(c))
+}
@@ -0,0 +1,129 @@
+package board
+
+import (
+	"fmt"
+
+	"github.com/tomaszmj/sudoku/set"
+)
+
+// Constraint is an additional rule of a sudoku variant (e.g. thermometer, arrow, kropki dots),
+// which can be attached to the board with AddConstraint. Standard rules (rows, columns, subgrids)
+// and built-in variants (diagonals, cages) are not constraints - they are handled by Board itself.
+// Constraints are compared by value in Board.Equal.
+type Constraint interface {
+	// Fields returns fields covered by the constraint.
+	Fields() []Field
+	// Check returns error if the constraint is broken on the board. Empty fields must not be
+	// treated as violation (unless the constraint cannot be satisfied whatever is put on them),
+	// so that Check can be used for partially filled boards.
+	Check(b *Board) error
+}
+
+// CandidatesRemover can be optionally implemented by Constraint to let solvers limit
+// numbers that they try to put on the fields covered by the constraint.
+type CandidatesRemover interface {
+	// RemoveCandidates removes from candidates of field x, y (covered by the constraint)
+	// numbers, which would break the constraint given other numbers on the board.
+	// Number on field x, y itself must be ignored (treated as empty).
+	RemoveCandidates(b *Board, x, y int, candidates *set.Set)
+}
+
+// Validator can be optionally implemented by Constraint to check its own requirements
+// (e.g. that its fields are adjacent), which are not checked by AddConstraint.
+type Validator interface {
+	// Validate returns error if the constraint is invalid. It is called by AddConstraint
+	// after checking that all fields of the constraint are on the board.
+	Validate() error
+}
+
+// AddConstraint attaches constraint to the board. All its fields must be on the board
+// and if the constraint implements Validator, Validate must not return error.
+// Constraints are kept by Copy, but they are not serialized.
+func (b *Board) AddConstraint(c Constraint) error {
+	fields := c.Fields()
+	if len(fields) == 0 {
+		return fmt.Errorf("constraint must cover at least 1 field")
+	}
+	for _, f := range fields {
+		if f.X < 0 || f.X >= b.gridSize || f.Y < 0 || f.Y >= b.gridSize {
+			return fmt.Errorf("constraint field (%d, %d) is outside of the board", f.X, f.Y)
+		}
+	}
+	if v, ok := c.(Validator); ok {
+		if err := v.Validate(); err != nil {
+			return err
+		}
+	}
+	constraintsOfField := b.constraintsOfField
+	if constraintsOfField == nil {
+		constraintsOfField = make([][]int, b.gridSize*b.gridSize)
+	}
+	for _, f := range fields {
+		offset := f.Y*b.gridSize + f.X
+		constraintsOfField[offset] = append(constraintsOfField[offset], len(b.constraints))
+	}
+	b.constraintsOfField = constraintsOfField
+	b.constraints = append(b.constraints, c)
+	return nil
+}
+
+// Constraints returns all constraints added with AddConstraint. The returned slice must not be modified.
+func (b *Board) Constraints() []Constraint {
+	return b.constraints
+}
+
+// ForEachConstraint calls operation for each constraint covering field x, y.
+func (b *Board) ForEachConstraint(x, y int, operation func(c Constraint)) {
+	if b.constraintsOfField == nil {
+		return
+	}
+	for _, i := range b.constraintsOfField[y*b.gridSize+x] {
+		operation(b.constraints[i])
+	}
+}
+
+// HaveCommonConstraint returns true if both fields are covered by the same constraint.
+func (b *Board) HaveCommonConstraint(x1, y1, x2, y2 int) bool {
+	if b.constraintsOfField == nil {
+		return false
+	}
+	for _, i := range b.constraintsOfField[y1*b.gridSize+x1] {
+		for _, j := range b.constraintsOfField[y2*b.gridSize+x2] {
+			if i == j {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// CheckConstraints returns error of the first broken constraint (see Constraint.Check) or nil.
+func (b *Board) CheckConstraints() error {
+	for i, c := range b.constraints {
+		if err := c.Check(b); err != nil {
+			return fmt.Errorf("constraint %d is broken: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// RemoveCandidates removes from candidates of field x, y numbers which would break
+// any constraint covering the field (see CandidatesRemover).
+func (b *Board) RemoveCandidates(x, y int, candidates *set.Set) {
+	b.ForEachConstraint(x, y, func(c Constraint) {
+		if remover, ok := c.(CandidatesRemover); ok {
+			remover.RemoveCandidates(b, x, y, candidates)
+		}
+	})
+}
+
+func (b *Board) copyConstraintsOfField() [][]int {
+	if b.constraintsOfField == nil {
+		return nil
+	}
+	constraintsOfField := make([][]int, len(b.constraintsOfField))
+	for i, constraints := range b.constraintsOfField {
+		constraintsOfField[i] = append([]int(nil), constraints...)
+	}
+	return constraintsOfField
+}
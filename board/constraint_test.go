@@ -0,0 +1,147 @@
+package board_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tomaszmj/sudoku/board"
+	"github.com/tomaszmj/sudoku/set"
+)
+
+func candidates(b *board.Board, x, y int) []int {
+	s := set.New(b.Size())
+	for n := 1; n <= b.Size(); n++ {
+		s.Add(n)
+	}
+	b.RemoveCandidates(x, y, s)
+	var numbers []int
+	s.ForEach(func(n int) bool {
+		numbers = append(numbers, n)
+		return false
+	})
+	return numbers
+}
+
+func TestAddConstraint(t *testing.T) {
+	b, err := board.New(3, 3)
+	require.NoError(t, err)
+	thermometer := board.Thermometer{{0, 0}, {1, 0}, {1, 1}}
+	dot := board.KropkiDot{A: board.Field{X: 1, Y: 1}, B: board.Field{X: 2, Y: 1}}
+	require.NoError(t, b.AddConstraint(thermometer))
+	require.NoError(t, b.AddConstraint(dot))
+	assert.Error(t, b.AddConstraint(board.Thermometer{}))
+	assert.Error(t, b.AddConstraint(board.Thermometer{{0, 9}}))
+	assert.Equal(t, []board.Constraint{thermometer, dot}, b.Constraints())
+
+	var covering []board.Constraint
+	b.ForEachConstraint(1, 1, func(c board.Constraint) {
+		covering = append(covering, c)
+	})
+	assert.Equal(t, []board.Constraint{thermometer, dot}, covering)
+	assert.True(t, b.HaveCommonConstraint(0, 0, 1, 1))
+	assert.True(t, b.HaveCommonConstraint(2, 1, 1, 1))
+	assert.False(t, b.HaveCommonConstraint(0, 0, 2, 1))
+
+	b2 := b.Copy()
+	assert.True(t, b.Equal(b2))
+	require.NoError(t, b2.AddConstraint(board.Thermometer{{5, 5}, {5, 6}}))
+	assert.False(t, b.Equal(b2))
+	assert.Len(t, b.Constraints(), 2)
+	assert.False(t, b.HaveCommonConstraint(5, 5, 5, 6))
+}
+
+// limitConstraint is a Constraint holding a pointer - numbers on its field must not exceed *max.
+type limitConstraint struct {
+	field board.Field
+	max   *int
+}
+
+func (l limitConstraint) Fields() []board.Field {
+	return []board.Field{l.field}
+}
+
+func (l limitConstraint) Check(b *board.Board) error {
+	if n := int(b.Get(l.field.X, l.field.Y)); n > *l.max {
+		return fmt.Errorf("number %d is greater than %d", n, *l.max)
+	}
+	return nil
+}
+
+func TestEqualConstraints(t *testing.T) {
+	newBoard := func(constraints ...board.Constraint) *board.Board {
+		b, err := board.New(2, 2)
+		require.NoError(t, err)
+		for _, c := range constraints {
+			require.NoError(t, b.AddConstraint(c))
+		}
+		return b
+	}
+	white := board.KropkiDot{A: board.Field{X: 0, Y: 0}, B: board.Field{X: 1, Y: 0}}
+	black := board.KropkiDot{A: board.Field{X: 0, Y: 0}, B: board.Field{X: 1, Y: 0}, Black: true}
+	thermometer := board.Thermometer{{0, 0}, {1, 0}}
+	max1, max2, otherMax2 := 1, 2, 2
+
+	// constraints are compared by value, so separately created constraints can be equal
+	assert.True(t, newBoard(white, thermometer).Equal(newBoard(white, board.Thermometer{{0, 0}, {1, 0}})))
+	assert.True(t, newBoard(limitConstraint{max: &max2}).Equal(newBoard(limitConstraint{max: &otherMax2})))
+	assert.False(t, newBoard(limitConstraint{max: &max1}).Equal(newBoard(limitConstraint{max: &max2})))
+	assert.False(t, newBoard(white).Equal(newBoard(black)))
+	assert.False(t, newBoard(white, thermometer).Equal(newBoard(thermometer, white)))
+	assert.False(t, newBoard(white).Equal(newBoard(white, white)))
+	assert.False(t, newBoard(white).Equal(newBoard()))
+}
+
+func TestThermometer(t *testing.T) {
+	b, err := board.New(3, 3)
+	require.NoError(t, err)
+	require.NoError(t, b.AddConstraint(board.Thermometer{{0, 0}, {1, 0}, {2, 0}, {2, 1}}))
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6}, candidates(b, 0, 0))
+	assert.Equal(t, []int{4, 5, 6, 7, 8, 9}, candidates(b, 2, 1))
+
+	b.Set(2, 0, 5)
+	assert.Equal(t, []int{2, 3, 4}, candidates(b, 1, 0))
+	assert.Equal(t, []int{6, 7, 8, 9}, candidates(b, 2, 1))
+	assert.Equal(t, []int{3, 4, 5, 6, 7, 8}, candidates(b, 2, 0)) // number on the field itself is ignored
+	assert.NoError(t, b.CheckRules())
+
+	b.Set(0, 0, 4)
+	var violations board.Violations
+	require.ErrorAs(t, b.CheckRules(), &violations)
+	require.Len(t, violations, 1)
+	assert.Equal(t, board.ConstraintUnit, violations[0].Unit)
+	assert.Equal(t, 0, violations[0].UnitIndex)
+	assert.Error(t, b.CheckConstraints())
+}
+
+func TestKropkiDot(t *testing.T) {
+	b, err := board.New(3, 3)
+	require.NoError(t, err)
+	require.NoError(t, b.AddConstraint(board.KropkiDot{A: board.Field{X: 0, Y: 0}, B: board.Field{X: 1, Y: 0}}))
+	require.NoError(t, b.AddConstraint(board.KropkiDot{A: board.Field{X: 0, Y: 1}, B: board.Field{X: 1, Y: 1}, Black: true}))
+	assert.Equal(t, []int{1, 2, 3, 4, 5, 6, 7, 8, 9}, candidates(b, 0, 0))
+	assert.Equal(t, []int{1, 2, 3, 4, 6, 8}, candidates(b, 0, 1))
+
+	b.Set(0, 0, 5)
+	b.Set(0, 1, 3)
+	assert.Equal(t, []int{4, 6}, candidates(b, 1, 0))
+	assert.Equal(t, []int{6}, candidates(b, 1, 1))
+	b.Set(1, 0, 6)
+	b.Set(1, 1, 6)
+	assert.NoError(t, b.CheckConstraints())
+	b.Set(1, 1, 9)
+	assert.EqualError(t, b.CheckConstraints(), "constraint 1 is broken: numbers 3 on (0, 1) and 9 on (1, 1) do not match black kropki dot")
+
+	// fields of the dot must be adjacent
+	require.NoError(t, b.AddConstraint(board.KropkiDot{A: board.Field{X: 2, Y: 2}, B: board.Field{X: 2, Y: 1}}))
+	for _, dot := range []board.KropkiDot{
+		{A: board.Field{X: 0, Y: 0}, B: board.Field{X: 2, Y: 0}},
+		{A: board.Field{X: 0, Y: 0}, B: board.Field{X: 1, Y: 1}},
+		{A: board.Field{X: 0, Y: 0}, B: board.Field{X: 0, Y: 0}},
+	} {
+		assert.EqualError(t, b.AddConstraint(dot), fmt.Sprintf("kropki dot fields (%d, %d) and (%d, %d) are not adjacent",
+			dot.A.X, dot.A.Y, dot.B.X, dot.B.Y))
+	}
+	assert.Len(t, b.Constraints(), 3)
+}
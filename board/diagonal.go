@@ -0,0 +1,83 @@
+package board
+
+const diagonalKeyword = "diagonal"
+
+// SetDiagonal enables or disables diagonal (X-Sudoku) constraint - if it is enabled,
+// each number must be placed exactly once also on both main diagonals of the board.
+// Diagonals are then treated as units (see Units) and fields on the same diagonal
+// are neighbours (see ForEachNeighbour). The constraint is kept by Copy and Serialize.
+func (b *Board) SetDiagonal(enabled bool) {
+	b.diagonal = enabled
+}
+
+// IsDiagonal returns true if diagonal (X-Sudoku) constraint is enabled (see SetDiagonal).
+func (b *Board) IsDiagonal() bool {
+	return b.diagonal
+}
+
+// diagonals returns fields of both diagonals - the first one goes from the top left corner,
+// the second one from the top right corner. It returns nil if diagonal constraint is disabled.
+func (b *Board) diagonals() [][]Field {
+	if !b.diagonal {
+		return nil
+	}
+	mainDiagonal := make([]Field, 0, b.gridSize)
+	antiDiagonal := make([]Field, 0, b.gridSize)
+	for i := 0; i < b.gridSize; i++ {
+		mainDiagonal = append(mainDiagonal, Field{i, i})
+		antiDiagonal = append(antiDiagonal, Field{b.gridSize - 1 - i, i})
+	}
+	return [][]Field{mainDiagonal, antiDiagonal}
+}
+
+func (b *Board) onMainDiagonal(x, y int) bool {
+	return x == y
+}
+
+func (b *Board) onAntiDiagonal(x, y int) bool {
+	return x+y == b.gridSize-1
+}
+
+// haveCommonDiagonal returns true if diagonal constraint is enabled
+// and both fields lie on the same diagonal.
+func (b *Board) haveCommonDiagonal(x1, y1, x2, y2 int) bool {
+	if !b.diagonal {
+		return false
+	}
+	return (b.onMainDiagonal(x1, y1) && b.onMainDiagonal(x2, y2)) ||
+		(b.onAntiDiagonal(x1, y1) && b.onAntiDiagonal(x2, y2))
+}
+
+// forEachDiagonalNeighbour is a part of ForEachNeighbour - it calls operation for fields
+// on the same diagonal as x0, y0, which are not in the same row, column or subgrid
+// (they have been already visited). No field is visited twice, because diagonals
+// cross only in the center of the board.
+func (b *Board) forEachDiagonalNeighbour(x0, y0 int, operation func(x, y int)) {
+	if !b.diagonal {
+		return
+	}
+	visit := func(x, y int) {
+		if x != x0 && y != y0 && !b.HaveCommonSubgrid(x0, y0, x, y) {
+			operation(x, y)
+		}
+	}
+	if b.onMainDiagonal(x0, y0) {
+		for i := 0; i < b.gridSize; i++ {
+			visit(i, i)
+		}
+	}
+	if b.onAntiDiagonal(x0, y0) {
+		for i := 0; i < b.gridSize; i++ {
+			visit(b.gridSize-1-i, i)
+		}
+	}
+}
+
+// diagonalHeaderSuffix returns suffix of the first line of serialized format
+// which enables diagonal constraint (see NewFromSerializedFormat).
+func (b *Board) diagonalHeaderSuffix() string {
+	if !b.diagonal {
+		return ""
+	}
+	return " " + diagonalKeyword
+}
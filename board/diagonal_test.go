@@ -0,0 +1,134 @@
+package board_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tomaszmj/sudoku/board"
+)
+
+func TestDiagonalUnits(t *testing.T) {
+	b, err := board.New(2, 2)
+	require.NoError(t, err)
+	assert.False(t, b.IsDiagonal())
+	require.Len(t, b.Units(), 12)
+	b.SetDiagonal(true)
+	assert.True(t, b.IsDiagonal())
+	units := b.Units()
+	require.Len(t, units, 14)
+	assert.Equal(t, board.Unit{
+		Kind:   board.Diagonal,
+		Index:  0,
+		Fields: []board.Field{{0, 0}, {1, 1}, {2, 2}, {3, 3}},
+	}, units[12])
+	assert.Equal(t, board.Unit{
+		Kind:   board.Diagonal,
+		Index:  1,
+		Fields: []board.Field{{3, 0}, {2, 1}, {1, 2}, {0, 3}},
+	}, units[13])
+}
+
+func TestDiagonalForEachNeighbour(t *testing.T) {
+	b, err := board.New(3, 3)
+	require.NoError(t, err)
+	b.SetDiagonal(true)
+	b.ForEachNeighbour(4, 4, func(x, y int) {
+		if b.Get(x, y) == 0 {
+			b.Set(x, y, 1)
+		} else {
+			b.Set(x, y, 2) // should not happen
+		}
+	})
+	assert.Equal(t, diagonalCenterNeighbourFilled, b.String())
+
+	jigsaw, err := board.NewJigsaw(jigsawRegionMap)
+	require.NoError(t, err)
+	jigsaw.SetDiagonal(true)
+	jigsaw.ForEachNeighbour(1, 2, func(x, y int) {
+		if jigsaw.Get(x, y) == 0 {
+			jigsaw.Set(x, y, 1)
+		} else {
+			jigsaw.Set(x, y, 2) // should not happen
+		}
+	})
+	assert.Equal(t, uint16(1), jigsaw.Get(3, 0))
+	assert.Equal(t, uint16(0), jigsaw.Get(3, 3))
+}
+
+func TestDiagonalHaveCommonUnit(t *testing.T) {
+	b, err := board.New(3, 3)
+	require.NoError(t, err)
+	assert.True(t, b.HaveCommonUnit(0, 0, 0, 8))
+	assert.True(t, b.HaveCommonUnit(0, 0, 2, 1))
+	assert.False(t, b.HaveCommonUnit(0, 0, 8, 8))
+	b.SetDiagonal(true)
+	assert.True(t, b.HaveCommonUnit(0, 0, 8, 8))
+	assert.True(t, b.HaveCommonUnit(8, 0, 4, 4))
+	assert.False(t, b.HaveCommonUnit(8, 0, 7, 7))
+}
+
+func TestDiagonalRules(t *testing.T) {
+	b, err := board.New(2, 2)
+	require.NoError(t, err)
+	b.Set(0, 0, 1)
+	b.Set(3, 3, 1)
+	assert.NoError(t, b.CheckRules())
+	b.SetDiagonal(true)
+	err = b.CheckRules()
+	var violations board.Violations
+	require.ErrorAs(t, err, &violations)
+	require.Len(t, violations, 1)
+	assert.Equal(t, board.Diagonal, violations[0].Unit)
+	assert.Equal(t, 0, violations[0].UnitIndex)
+}
+
+func TestDiagonalSerialization(t *testing.T) {
+	t.Run("board can be recreated from string", func(t *testing.T) {
+		board1, err := board.New(3, 2)
+		require.NoError(t, err)
+		board1.SetDiagonal(true)
+		board1.Set(1, 2, 3)
+		var serializeOutput strings.Builder
+		require.NoError(t, board1.Serialize(&serializeOutput))
+		assert.True(t, strings.HasPrefix(serializeOutput.String(), "3 2 diagonal\n"))
+		board2, err := board.NewFromSerializedFormat(strings.NewReader(serializeOutput.String()))
+		require.NoError(t, err)
+		assert.True(t, board2.IsDiagonal())
+		assert.True(t, board1.Equal(board2))
+		assert.True(t, board1.Copy().Equal(board2))
+	})
+	t.Run("jigsaw board can be recreated from string", func(t *testing.T) {
+		board1, err := board.NewJigsaw(jigsawRegionMap)
+		require.NoError(t, err)
+		board1.SetDiagonal(true)
+		var serializeOutput strings.Builder
+		require.NoError(t, board1.Serialize(&serializeOutput))
+		board2, err := board.NewFromSerializedFormat(strings.NewReader(serializeOutput.String()))
+		require.NoError(t, err)
+		assert.Equal(t, board1, board2)
+	})
+	t.Run("diagonal board is not equal to regular board", func(t *testing.T) {
+		board1, err := board.New(2, 2)
+		require.NoError(t, err)
+		board2 := board1.Copy()
+		board2.SetDiagonal(true)
+		assert.False(t, board1.Equal(board2))
+	})
+}
+
+const diagonalCenterNeighbourFilled = `+-------+-------+-------+
+| 1 0 0 | 0 1 0 | 0 0 1 |
+| 0 1 0 | 0 1 0 | 0 1 0 |
+| 0 0 1 | 0 1 0 | 1 0 0 |
++-------+-------+-------+
+| 0 0 0 | 1 1 1 | 0 0 0 |
+| 1 1 1 | 1 0 1 | 1 1 1 |
+| 0 0 0 | 1 1 1 | 0 0 0 |
++-------+-------+-------+
+| 0 0 1 | 0 1 0 | 1 0 0 |
+| 0 1 0 | 0 1 0 | 0 1 0 |
+| 1 0 0 | 0 1 0 | 0 0 1 |
++-------+-------+-------+
+`
@@ -0,0 +1,38 @@
+package board
+
+// SetGiven marks field x, y as given (a clue of the puzzle) or not. Givens only describe
+// the puzzle - they do not affect sudoku rules and solvers, but they are kept by Copy,
+// so solutions found by solvers know which numbers were given. Equal ignores them.
+// Parsers do not mark givens (see MarkGivens).
+func (b *Board) SetGiven(x, y int, given bool) {
+	if b.givens == nil {
+		if !given {
+			return
+		}
+		b.givens = make([]bool, b.gridSize*b.gridSize)
+	}
+	b.givens[y*b.gridSize+x] = given
+}
+
+// IsGiven returns true if field x, y has been marked as given (see SetGiven).
+func (b *Board) IsGiven(x, y int) bool {
+	return b.givens != nil && b.givens[y*b.gridSize+x]
+}
+
+// HasGivens returns true if any field has been marked as given (see SetGiven).
+func (b *Board) HasGivens() bool {
+	for _, given := range b.givens {
+		if given {
+			return true
+		}
+	}
+	return false
+}
+
+// MarkGivens marks all fields with numbers as givens and empty fields as not given.
+func (b *Board) MarkGivens() {
+	b.givens = nil
+	for i, n := range b.data {
+		b.SetGiven(i%b.gridSize, i/b.gridSize, n != 0)
+	}
+}
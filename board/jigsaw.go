@@ -0,0 +1,219 @@
+package board
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const jigsawKeyword = "jigsaw"
+
+// NewJigsaw creates empty board, in which subgrids (regions) can have any shape.
+// regionMap holds region index of each field (regionMap[y][x]). The board is
+// size x size, where size is len(regionMap), and it must be split into size regions
+// (indexed from 0 to size-1), each containing size fields. Regions do not
+// have to be contiguous. For example, the following region map:
+// 0 0 0 1
+// 2 0 1 1
+// 2 2 3 1
+// 2 3 3 3
+// creates board:
+// +-----------+---+
+// | 0   0   0 | 0 |
+// +---+   +---+   +
+// | 0 | 0 | 0   0 |
+// +   +---+---+   +
+// | 0   0 | 0 | 0 |
+// +   +---+   +---+
+// | 0 | 0   0   0 |
+// +---+-----------+
+func NewJigsaw(regionMap [][]int) (*Board, error) {
+	size := len(regionMap)
+	if size < 1 || size > MaxSize {
+		return nil, fmt.Errorf("invalid grid size, it must be from 1 to %d, got %d", MaxSize, size)
+	}
+	regions := make([]int, 0, size*size)
+	regionFields := make([][]Field, size)
+	for y, row := range regionMap {
+		if len(row) != size {
+			return nil, fmt.Errorf("invalid region map row %d length, expected %d, got %d", y, size, len(row))
+		}
+		for x, region := range row {
+			if region < 0 || region >= size {
+				return nil, fmt.Errorf("invalid region %d at %d, %d - it must be from 0 to %d", region, x, y, size-1)
+			}
+			regions = append(regions, region)
+			regionFields[region] = append(regionFields[region], Field{x, y})
+		}
+	}
+	for region, fields := range regionFields {
+		if len(fields) != size {
+			return nil, fmt.Errorf("region %d has %d fields, expected %d", region, len(fields), size)
+		}
+	}
+	return &Board{
+		data:         make([]uint16, size*size),
+		gridSize:     size,
+		regions:      regions,
+		regionFields: regionFields,
+	}, nil
+}
+
+// newJigsawFromSerializedFormat is a part of NewFromSerializedFormat for jigsaw boards,
+// called after reading the first line (which is in scanner). Format of jigsaw board is:
+// jigsaw <size>
+// <size lines of board data>
+// <size lines of region map>
+// Just like for boards with regular subgrids, lines without numbers are ignored,
+// so output of String can be used as board data. For example:
+// jigsaw 2
+// +-------+
+// | 1   2 |
+// +-------+
+// | 2   1 |
+// +-------+
+// regions:
+// 0 0
+// 1 1
+// Header is the first line without alphabet specification (see SetAlphabet).
+func newJigsawFromSerializedFormat(scanner *bufio.Scanner, header string, alphabet *Alphabet) (*Board, error) {
+	firstLineNumbers := findNumbersRegex.FindAllString(header, 2) // 2 instead of 1 to find if there are too many numbers
+	if len(firstLineNumbers) != 1 {
+		return nil, fmt.Errorf("error parsing - expected 1 number, line: %s", scanner.Text())
+	}
+	size, err := strconv.Atoi(firstLineNumbers[0])
+	if err != nil {
+		return nil, fmt.Errorf("error parsing number %w in line: %s", err, scanner.Text())
+	}
+	if size < 1 || size > MaxSize {
+		return nil, fmt.Errorf("invalid grid size, it must be from 1 to %d, got %d", MaxSize, size)
+	}
+	if alphabet != nil && alphabet.Size() < size {
+		return nil, fmt.Errorf("alphabet %s has %d symbols, board size is %d", alphabet, alphabet.Size(), size)
+	}
+	lines := &numberLinesScanner{scanner: scanner, lineNumber: 1}
+	rows, err := lines.scanRows("board", size, size, alphabet)
+	if err != nil {
+		return nil, err
+	}
+	regionMap, err := lines.scanRows("region map", size, size-1, nil)
+	if err != nil {
+		return nil, err
+	}
+	cages, err := lines.scanCages("region map", size)
+	if err != nil {
+		return nil, err
+	}
+	board, err := NewJigsaw(regionMap)
+	if err != nil {
+		return nil, fmt.Errorf("error creating board: %w", err)
+	}
+	board.setRows(rows)
+	board.alphabet = alphabet
+	if err := board.addCages(cages); err != nil {
+		return nil, err
+	}
+	return board, nil
+}
+
+func (b *Board) serializeJigsaw(writer io.Writer) error {
+	var s strings.Builder
+	s.WriteString(fmt.Sprintf("%s %d%s%s\n", jigsawKeyword, b.gridSize, b.diagonalHeaderSuffix(), b.alphabetHeaderSuffix()))
+	s.WriteString(b.jigsawString())
+	s.WriteString("regions:\n")
+	regionLen := len(fmt.Sprint(b.gridSize - 1))
+	for y := 0; y < b.gridSize; y++ {
+		for x := 0; x < b.gridSize; x++ {
+			if x > 0 {
+				s.WriteString(" ")
+			}
+			s.WriteString(fmt.Sprintf("%*d", regionLen, b.regions[y*b.gridSize+x]))
+		}
+		s.WriteString("\n")
+	}
+	if _, err := io.WriteString(writer, s.String()); err != nil {
+		return err
+	}
+	return b.serializeCages(writer)
+}
+
+func (b *Board) forEachJigsawNeighbour(x0, y0 int, operation func(x, y int)) {
+	for y := 0; y < b.gridSize; y++ {
+		if y != y0 {
+			operation(x0, y)
+		}
+	}
+	for x := 0; x < b.gridSize; x++ {
+		if x != x0 {
+			operation(x, y0)
+		}
+	}
+	for _, f := range b.regionFields[b.regions[y0*b.gridSize+x0]] {
+		if f.X != x0 && f.Y != y0 {
+			operation(f.X, f.Y)
+		}
+	}
+}
+
+// jigsawString is String for jigsaw boards - region borders
+// are drawn between fields from different regions (see NewJigsaw).
+func (b *Board) jigsawString() string {
+	var s strings.Builder
+	digitLen := b.numberWidth()
+	horizontalLine := strings.Repeat("-", digitLen+2)
+	emptyLine := strings.Repeat(" ", digitLen+2)
+	for y := 0; y <= b.gridSize; y++ {
+		for x := 0; x <= b.gridSize; x++ {
+			switch {
+			case b.verticalBorder(x, y-1) || b.verticalBorder(x, y):
+				s.WriteString("+")
+			case b.horizontalBorder(x-1, y) || b.horizontalBorder(x, y):
+				s.WriteString("-")
+			default:
+				s.WriteString(" ")
+			}
+			if x == b.gridSize {
+				break
+			}
+			if b.horizontalBorder(x, y) {
+				s.WriteString(horizontalLine)
+			} else {
+				s.WriteString(emptyLine)
+			}
+		}
+		s.WriteString("\n")
+		if y == b.gridSize {
+			break
+		}
+		for x := 0; x <= b.gridSize; x++ {
+			if b.verticalBorder(x, y) {
+				s.WriteString("|")
+			} else {
+				s.WriteString(" ")
+			}
+			if x < b.gridSize {
+				s.WriteString(" " + b.formatNumber(b.Get(x, y), digitLen) + " ")
+			}
+		}
+		s.WriteString("\n")
+	}
+	return s.String()
+}
+
+// horizontalBorder returns true if there is region border above field x, y.
+func (b *Board) horizontalBorder(x, y int) bool {
+	if x < 0 || x >= b.gridSize {
+		return false
+	}
+	return y == 0 || y == b.gridSize || b.regions[(y-1)*b.gridSize+x] != b.regions[y*b.gridSize+x]
+}
+
+// verticalBorder returns true if there is region border on the left of field x, y.
+func (b *Board) verticalBorder(x, y int) bool {
+	if y < 0 || y >= b.gridSize {
+		return false
+	}
+	return x == 0 || x == b.gridSize || b.regions[y*b.gridSize+x-1] != b.regions[y*b.gridSize+x]
+}
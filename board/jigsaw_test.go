@@ -0,0 +1,143 @@
+package board_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tomaszmj/sudoku/board"
+)
+
+var jigsawRegionMap = [][]int{
+	{0, 0, 0, 1},
+	{2, 0, 1, 1},
+	{2, 2, 3, 1},
+	{2, 3, 3, 3},
+}
+
+func TestNewJigsaw(t *testing.T) {
+	t.Run("board is created empty", func(t *testing.T) {
+		b, err := board.NewJigsaw(jigsawRegionMap)
+		require.NoError(t, err)
+		assert.True(t, b.IsJigsaw())
+		assert.Equal(t, 4, b.Size())
+		assert.Equal(t, jigsaw4x4Zeros, b.String())
+	})
+	t.Run("empty region map", func(t *testing.T) {
+		_, err := board.NewJigsaw(nil)
+		assert.Error(t, err)
+	})
+	t.Run("row too short", func(t *testing.T) {
+		_, err := board.NewJigsaw([][]int{{0, 0}, {1}})
+		assert.Error(t, err)
+	})
+	t.Run("invalid region index", func(t *testing.T) {
+		_, err := board.NewJigsaw([][]int{{0, 0}, {1, 2}})
+		assert.Error(t, err)
+	})
+	t.Run("regions of different sizes", func(t *testing.T) {
+		_, err := board.NewJigsaw([][]int{{0, 0}, {0, 1}})
+		assert.Error(t, err)
+	})
+}
+
+func TestJigsawUnits(t *testing.T) {
+	b, err := board.NewJigsaw(jigsawRegionMap)
+	require.NoError(t, err)
+	units := b.Units()
+	require.Len(t, units, 12)
+	assert.Equal(t, board.Unit{
+		Kind:   board.Subgrid,
+		Index:  2,
+		Fields: []board.Field{{0, 1}, {0, 2}, {1, 2}, {0, 3}},
+	}, units[10])
+	assert.Equal(t, 2, b.Subgrid(1, 2))
+	assert.Equal(t, 1, b.Subgrid(3, 2))
+	assert.True(t, b.HaveCommonSubgrid(1, 1, 2, 0))
+	assert.False(t, b.HaveCommonSubgrid(1, 1, 1, 2))
+}
+
+func TestJigsawForEachNeighbour(t *testing.T) {
+	b, err := board.NewJigsaw(jigsawRegionMap)
+	require.NoError(t, err)
+	b.ForEachNeighbour(1, 2, func(x, y int) {
+		if b.Get(x, y) == 0 {
+			b.Set(x, y, 1)
+		} else {
+			b.Set(x, y, 2) // should not happen
+		}
+	})
+	assert.Equal(t, jigsaw4x4NeighbourFilled, b.String())
+}
+
+func TestJigsawSerialization(t *testing.T) {
+	t.Run("board can be recreated from string", func(t *testing.T) {
+		board1, err := board.NewJigsaw(jigsawRegionMap)
+		require.NoError(t, err)
+		board1.Set(1, 2, 3)
+		board1.Set(3, 0, 4)
+		var serializeOutput strings.Builder
+		require.NoError(t, board1.Serialize(&serializeOutput))
+		board2, err := board.NewFromSerializedFormat(strings.NewReader(serializeOutput.String()))
+		require.NoError(t, err)
+		assert.Equal(t, board1, board2)
+		assert.True(t, board1.Equal(board2))
+	})
+	t.Run("jigsaw is not equal to regular board", func(t *testing.T) {
+		board1, err := board.NewJigsaw([][]int{{0, 0, 1, 1}, {0, 0, 1, 1}, {2, 2, 3, 3}, {2, 2, 3, 3}})
+		require.NoError(t, err)
+		board2, err := board.New(2, 2)
+		require.NoError(t, err)
+		assert.False(t, board1.Equal(board2))
+		assert.False(t, board2.Equal(board1))
+	})
+	t.Run("compact format", func(t *testing.T) {
+		b, err := board.NewFromSerializedFormat(strings.NewReader("jigsaw 2\n1 2\n2 1\n\n0 0\n1 1\n"))
+		require.NoError(t, err)
+		assert.Equal(t, uint16(2), b.Get(1, 0))
+		assert.True(t, b.HaveCommonSubgrid(0, 0, 1, 0))
+	})
+	t.Run("missing region map", func(t *testing.T) {
+		_, err := board.NewFromSerializedFormat(strings.NewReader("jigsaw 2\n1 2\n2 1\n"))
+		assert.Error(t, err)
+	})
+	t.Run("too many lines", func(t *testing.T) {
+		_, err := board.NewFromSerializedFormat(strings.NewReader("jigsaw 2\n1 2\n2 1\n0 0\n1 1\n0 0\n"))
+		assert.Error(t, err)
+	})
+	t.Run("invalid region index", func(t *testing.T) {
+		_, err := board.NewFromSerializedFormat(strings.NewReader("jigsaw 2\n1 2\n2 1\n0 0\n1 2\n"))
+		assert.Error(t, err)
+	})
+	t.Run("invalid region map", func(t *testing.T) {
+		_, err := board.NewFromSerializedFormat(strings.NewReader("jigsaw 2\n1 2\n2 1\n0 0\n0 1\n"))
+		assert.Error(t, err)
+	})
+	t.Run("invalid header", func(t *testing.T) {
+		_, err := board.NewFromSerializedFormat(strings.NewReader("jigsaw 2 2\n1 2\n2 1\n0 0\n1 1\n"))
+		assert.Error(t, err)
+	})
+}
+
+const jigsaw4x4Zeros = `+-----------+---+
+| 0   0   0 | 0 |
++---+   +---+   +
+| 0 | 0 | 0   0 |
++   +---+---+   +
+| 0   0 | 0 | 0 |
++   +---+   +---+
+| 0 | 0   0   0 |
++---+-----------+
+`
+
+const jigsaw4x4NeighbourFilled = `+-----------+---+
+| 0   1   0 | 0 |
++---+   +---+   +
+| 1 | 1 | 0   0 |
++   +---+---+   +
+| 1   0 | 1 | 1 |
++   +---+   +---+
+| 1 | 1   0   0 |
++---+-----------+
+`
@@ -0,0 +1,182 @@
+package board
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// boardJSON is JSON schema of Board, for example:
+// {"subgridWidth": 2, "subgridHeight": 1, "cells": [[1, 0], [0, 2]],
+// "givens": [[true, false], [false, false]], "metadata": {"name": "tiny"}}
+// Cells are rows of numbers (0 is empty field), so cells[y][x] is number on field x, y.
+// Givens (see SetGiven) have the same shape as cells, they are present only if any field is given.
+// Jigsaw boards have regions (the same as region map of NewJigsaw) instead of subgrid size.
+// Diagonal constraint and Killer Sudoku cages are included as well, but other constraints
+// (see AddConstraint) are not supported.
+type boardJSON struct {
+	SubgridWidth  int        `json:"subgridWidth,omitempty"`
+	SubgridHeight int        `json:"subgridHeight,omitempty"`
+	Regions       [][]int    `json:"regions,omitempty"`
+	Diagonal      bool       `json:"diagonal,omitempty"`
+	Cells         [][]int    `json:"cells"`
+	Givens        [][]bool   `json:"givens,omitempty"`
+	Cages         []cageJSON `json:"cages,omitempty"`
+	Metadata      *Metadata  `json:"metadata,omitempty"`
+}
+
+// cageJSON is JSON schema of Cage - fields are [x, y] pairs.
+type cageJSON struct {
+	Sum    int     `json:"sum"`
+	Fields [][]int `json:"fields"`
+}
+
+// MarshalJSON encodes board in JSON (see boardJSON for the schema).
+// It returns error if the board has constraints added with AddConstraint.
+func (b *Board) MarshalJSON() ([]byte, error) {
+	if len(b.constraints) > 0 {
+		return nil, fmt.Errorf("boards with constraints cannot be encoded in JSON")
+	}
+	j := boardJSON{
+		SubgridWidth:  b.subgridWidth,
+		SubgridHeight: b.subgridHeight,
+		Diagonal:      b.diagonal,
+		Cells:         make([][]int, b.gridSize),
+	}
+	for y := range j.Cells {
+		j.Cells[y] = make([]int, b.gridSize)
+		for x := range j.Cells[y] {
+			j.Cells[y][x] = int(b.Get(x, y))
+		}
+	}
+	if b.regions != nil {
+		j.Regions = make([][]int, b.gridSize)
+		for y := range j.Regions {
+			j.Regions[y] = append([]int(nil), b.regions[y*b.gridSize:(y+1)*b.gridSize]...)
+		}
+	}
+	if b.HasGivens() {
+		j.Givens = make([][]bool, b.gridSize)
+		for y := range j.Givens {
+			j.Givens[y] = append([]bool(nil), b.givens[y*b.gridSize:(y+1)*b.gridSize]...)
+		}
+	}
+	for _, cage := range b.cages {
+		fields := make([][]int, 0, len(cage.Fields))
+		for _, f := range cage.Fields {
+			fields = append(fields, []int{f.X, f.Y})
+		}
+		j.Cages = append(j.Cages, cageJSON{Sum: cage.Sum, Fields: fields})
+	}
+	if !b.metadata.IsEmpty() {
+		metadata := b.metadata
+		j.Metadata = &metadata
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON decodes board encoded by MarshalJSON. Unknown keys are not allowed.
+// Errors describe which part of the input is invalid.
+func (b *Board) UnmarshalJSON(data []byte) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var j boardJSON
+	if err := decoder.Decode(&j); err != nil {
+		return fmt.Errorf("error decoding board: %w", err)
+	}
+	board, err := j.board()
+	if err != nil {
+		return err
+	}
+	*b = *board
+	return nil
+}
+
+func (j *boardJSON) board() (*Board, error) {
+	if j.Regions != nil && (j.SubgridWidth != 0 || j.SubgridHeight != 0) {
+		return nil, fmt.Errorf("jigsaw board with regions must not have subgrid size")
+	}
+	// cells are validated before the board is created, so that the size of allocated board
+	// is limited by the size of the input
+	if err := j.validateCells(); err != nil {
+		return nil, err
+	}
+	var board *Board
+	var err error
+	if j.Regions != nil {
+		board, err = NewJigsaw(j.Regions)
+	} else {
+		board, err = New(j.SubgridWidth, j.SubgridHeight)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("error creating board: %w", err)
+	}
+	for y, row := range j.Cells {
+		for x, n := range row {
+			board.Set(x, y, uint16(n))
+		}
+	}
+	if j.Givens != nil {
+		if len(j.Givens) != board.gridSize {
+			return nil, fmt.Errorf("invalid number of givens rows, expected %d, got %d", board.gridSize, len(j.Givens))
+		}
+		for y, row := range j.Givens {
+			if len(row) != board.gridSize {
+				return nil, fmt.Errorf("expected %d values in givens row %d, got %d", board.gridSize, y, len(row))
+			}
+			for x, given := range row {
+				if given && board.Get(x, y) == 0 {
+					return nil, fmt.Errorf("field (%d, %d) is given, but it is empty", x, y)
+				}
+				board.SetGiven(x, y, given)
+			}
+		}
+	}
+	board.diagonal = j.Diagonal
+	for i, cage := range j.Cages {
+		fields := make([]Field, 0, len(cage.Fields))
+		for _, f := range cage.Fields {
+			if len(f) != 2 {
+				return nil, fmt.Errorf("invalid field %v of cage %d, expected [x, y]", f, i)
+			}
+			fields = append(fields, Field{f[0], f[1]})
+		}
+		if err := board.AddCage(cage.Sum, fields); err != nil {
+			return nil, fmt.Errorf("invalid cage %d: %w", i, err)
+		}
+	}
+	if j.Metadata != nil {
+		board.metadata = *j.Metadata
+	}
+	return board, nil
+}
+
+// validateCells checks that cells have grid size rows, each with grid size numbers from 0 to grid size.
+// Invalid subgrid size is not reported here (creating the board fails then).
+func (j *boardJSON) validateCells() error {
+	size := len(j.Regions)
+	if j.Regions == nil {
+		if j.SubgridWidth < 1 || j.SubgridHeight < 1 || j.SubgridWidth > MaxSize || j.SubgridHeight > MaxSize ||
+			j.SubgridWidth*j.SubgridHeight > MaxSize {
+			return nil
+		}
+		size = j.SubgridWidth * j.SubgridHeight
+	}
+	if j.Cells == nil {
+		return fmt.Errorf("missing cells")
+	}
+	if len(j.Cells) != size {
+		return fmt.Errorf("invalid number of cells rows, expected %d, got %d", size, len(j.Cells))
+	}
+	for y, row := range j.Cells {
+		if len(row) != size {
+			return fmt.Errorf("expected %d numbers in cells row %d, got %d", size, y, len(row))
+		}
+		for x, n := range row {
+			if n < 0 || n > size {
+				return fmt.Errorf("invalid number %d in cells row %d, column %d - it must be from 0 to %d", n, y, x, size)
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,135 @@
+package board_test
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tomaszmj/sudoku/board"
+)
+
+func TestBoardJSON(t *testing.T) {
+	t.Run("schema", func(t *testing.T) {
+		b, err := board.New(2, 1)
+		require.NoError(t, err)
+		b.Set(0, 0, 1)
+		b.Set(1, 1, 2)
+		b.SetGiven(0, 0, true)
+		b.SetMetadata(board.Metadata{Name: "tiny"})
+		data, err := json.Marshal(b)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"subgridWidth": 2, "subgridHeight": 1, "cells": [[1, 0], [0, 2]],
+			"givens": [[true, false], [false, false]], "metadata": {"name": "tiny"}}`, string(data))
+
+		var decoded board.Board
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		assert.Equal(t, b, &decoded)
+		assert.True(t, decoded.IsGiven(0, 0))
+		assert.False(t, decoded.IsGiven(1, 1))
+	})
+
+	t.Run("optional keys are omitted", func(t *testing.T) {
+		b, err := board.New(1, 1)
+		require.NoError(t, err)
+		data, err := json.Marshal(b)
+		require.NoError(t, err)
+		assert.JSONEq(t, `{"subgridWidth": 1, "subgridHeight": 1, "cells": [[0]]}`, string(data))
+	})
+
+	t.Run("round trip of variants", func(t *testing.T) {
+		for _, path := range []string{
+			"../cmd/boards/very_difficult_9x9.txt",
+			"../cmd/boards/12x12.txt",
+			"../cmd/boards/jigsaw9x9.txt",
+			"../cmd/boards/diagonal9x9.txt",
+			"../cmd/boards/killer9x9.txt",
+		} {
+			file, err := os.Open(path)
+			require.NoError(t, err)
+			b, err := board.NewFromSerializedFormat(file)
+			file.Close()
+			require.NoError(t, err)
+			b.MarkGivens()
+			data, err := json.Marshal(b)
+			require.NoError(t, err)
+			var decoded *board.Board
+			require.NoError(t, json.Unmarshal(data, &decoded))
+			assert.Equal(t, b, decoded, path)
+		}
+	})
+
+	t.Run("solutions in JSON", func(t *testing.T) {
+		type result struct {
+			Solutions []*board.Board `json:"solutions"`
+		}
+		b, err := board.New(2, 2)
+		require.NoError(t, err)
+		data, err := json.Marshal(result{Solutions: []*board.Board{b, b}})
+		require.NoError(t, err)
+		var decoded result
+		require.NoError(t, json.Unmarshal(data, &decoded))
+		require.Len(t, decoded.Solutions, 2)
+		assert.True(t, b.Equal(decoded.Solutions[1]))
+	})
+
+	t.Run("constraints are not supported", func(t *testing.T) {
+		b, err := board.New(2, 2)
+		require.NoError(t, err)
+		require.NoError(t, b.AddConstraint(board.Thermometer{{X: 0, Y: 0}, {X: 1, Y: 0}}))
+		_, err = json.Marshal(b)
+		assert.Error(t, err)
+	})
+
+	t.Run("invalid input", func(t *testing.T) {
+		for _, tc := range []struct {
+			input         string
+			expectedError string
+		}{
+			{`[]`, "error decoding board"},
+			{`{"subgridWidth": 1, "subgridHeight": 1, "cells": [[0]], "unknown": 1}`, "unknown field"},
+			{`{"subgridWidth": 0, "subgridHeight": 1, "cells": []}`, "invalid grid size"},
+			{`{"subgridWidth": 1, "subgridHeight": 1}`, "missing cells"},
+			// the board must not be allocated before cells are checked
+			{`{"subgridWidth": 200, "subgridHeight": 200, "cells": []}`, "invalid number of cells rows, expected 40000, got 0"},
+			{`{"subgridWidth": 200, "subgridHeight": 200, "cells": [[], []]}`, "invalid number of cells rows, expected 40000, got 2"},
+			{`{"regions": [[0, 0], [1, 1]], "cells": [[0, 0], [0, 0], [0, 0]]}`, "invalid number of cells rows, expected 2, got 3"},
+			{`{"subgridWidth": 300, "subgridHeight": 300, "cells": []}`, "grid size (90000) > max available grid size"},
+			{`{"subgridWidth": 2, "subgridHeight": 1, "cells": [[0, 0]]}`, "invalid number of cells rows, expected 2, got 1"},
+			{`{"subgridWidth": 2, "subgridHeight": 1, "cells": [[0, 0], [0]]}`, "expected 2 numbers in cells row 1, got 1"},
+			{`{"subgridWidth": 2, "subgridHeight": 1, "cells": [[0, 3], [0, 0]]}`, "invalid number 3 in cells row 0, column 1"},
+			{`{"subgridWidth": 2, "subgridHeight": 1, "cells": [[0, -1], [0, 0]]}`, "invalid number -1 in cells row 0, column 1"},
+			{`{"subgridWidth": 2, "subgridHeight": 1, "cells": [[0, 0], [0, 0]], "givens": [[true, false], [false, false]]}`, "field (0, 0) is given, but it is empty"},
+			{`{"subgridWidth": 2, "subgridHeight": 1, "cells": [[1, 0], [0, 0]], "givens": [[true, false]]}`, "invalid number of givens rows"},
+			{`{"subgridWidth": 2, "subgridHeight": 1, "regions": [[0, 0], [1, 1]], "cells": [[0, 0], [0, 0]]}`, "must not have subgrid size"},
+			{`{"regions": [[0, 0], [0, 1]], "cells": [[0, 0], [0, 0]]}`, "region 0 has 3 fields"},
+			{`{"subgridWidth": 2, "subgridHeight": 1, "cells": [[0, 0], [0, 0]], "cages": [{"sum": 3, "fields": [[0, 0], [1]]}]}`, "invalid field [1] of cage 0"},
+			{`{"subgridWidth": 2, "subgridHeight": 1, "cells": [[0, 0], [0, 0]], "cages": [{"sum": 5, "fields": [[0, 0], [1, 0]]}]}`, "invalid cage 0: invalid cage sum 5"},
+		} {
+			var b board.Board
+			err := json.Unmarshal([]byte(tc.input), &b)
+			require.Error(t, err, tc.input)
+			assert.Contains(t, err.Error(), tc.expectedError)
+		}
+	})
+}
+
+func TestGivens(t *testing.T) {
+	b, err := board.New(2, 2)
+	require.NoError(t, err)
+	b.Set(1, 0, 3)
+	assert.False(t, b.HasGivens())
+	b.MarkGivens()
+	assert.True(t, b.HasGivens())
+	assert.True(t, b.IsGiven(1, 0))
+	assert.False(t, b.IsGiven(0, 0))
+
+	// givens are copied, but ignored by Equal
+	c := b.Copy()
+	assert.True(t, c.IsGiven(1, 0))
+	c.SetGiven(1, 0, false)
+	assert.True(t, b.IsGiven(1, 0))
+	assert.False(t, c.HasGivens())
+	assert.True(t, b.Equal(c))
+}
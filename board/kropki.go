@@ -0,0 +1,73 @@
+package board
+
+import (
+	"fmt"
+
+	"github.com/tomaszmj/sudoku/set"
+)
+
+// KropkiDot is a Constraint between two adjacent fields - if the dot is white (Black is false),
+// numbers on the fields must be consecutive, if the dot is black, one number must be double the other.
+// Fields are adjacent if they are next to each other in the same row or column (see Validate).
+type KropkiDot struct {
+	A, B  Field
+	Black bool
+}
+
+func (k KropkiDot) Fields() []Field {
+	return []Field{k.A, k.B}
+}
+
+// Validate returns error if fields of the dot are not adjacent.
+func (k KropkiDot) Validate() error {
+	dx, dy := k.A.X-k.B.X, k.A.Y-k.B.Y
+	if dx*dx+dy*dy != 1 {
+		return fmt.Errorf("kropki dot fields (%d, %d) and (%d, %d) are not adjacent", k.A.X, k.A.Y, k.B.X, k.B.Y)
+	}
+	return nil
+}
+
+func (k KropkiDot) Check(b *Board) error {
+	n1, n2 := int(b.Get(k.A.X, k.A.Y)), int(b.Get(k.B.X, k.B.Y))
+	if n1 == 0 || n2 == 0 || k.match(n1, n2) {
+		return nil
+	}
+	return fmt.Errorf("numbers %d on (%d, %d) and %d on (%d, %d) do not match %s kropki dot",
+		n1, k.A.X, k.A.Y, n2, k.B.X, k.B.Y, k.color())
+}
+
+func (k KropkiDot) RemoveCandidates(b *Board, x, y int, candidates *set.Set) {
+	other := k.B
+	if other.X == x && other.Y == y {
+		other = k.A
+	}
+	otherNumber := int(b.Get(other.X, other.Y))
+	candidates.ForEach(func(n int) bool {
+		matching := false
+		if otherNumber != 0 {
+			matching = k.match(n, otherNumber)
+		} else {
+			for m := 1; m <= b.Size() && !matching; m++ {
+				matching = m != n && k.match(n, m)
+			}
+		}
+		if !matching {
+			candidates.Remove(n)
+		}
+		return false
+	})
+}
+
+func (k KropkiDot) match(n1, n2 int) bool {
+	if k.Black {
+		return n1 == 2*n2 || n2 == 2*n1
+	}
+	return n1 == n2+1 || n2 == n1+1
+}
+
+func (k KropkiDot) color() string {
+	if k.Black {
+		return "black"
+	}
+	return "white"
+}
@@ -0,0 +1,170 @@
+package board
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// lineDigits are characters used for numbers in one-line format - digits followed by letters,
+// so the largest grid which can be written in one line is 35x35. Index of a character is its number.
+const lineDigits = ".123456789ABCDEFGHIJKLMNOPQRSTUVWXYZ"
+
+// maxLineSize is the largest grid size which can be written in one-line format.
+const maxLineSize = len(lineDigits) - 1
+
+// lineRegex matches one-line format (see NewFromLine), with optional subgrid size prefix.
+var lineRegex = regexp.MustCompile(`^(?:(\d+)x(\d+):)?([.0-9A-Za-z]+)$`)
+
+// NewFromLine creates board from one-line format commonly used by puzzle collections -
+// numbers of all fields row by row, with "." or "0" for empty fields, for example 9x9 board is:
+// 4.....8.5.3..........7......2.....6.....8.4......1.......6.3.7.5..2.....1.4......
+// Numbers larger than 9 are written as letters (A is 10, B is 11 and so on, case does not matter).
+// Subgrid size is inferred from the length of the line - subgrid is as close to square as possible,
+// with width not smaller than height (e.g. 3x2 for 6x6 board, 4x3 for 12x12 board).
+// Other subgrid size can be given as a prefix "<width>x<height>:", for example:
+// 3x4:............................................................................................................................................
+// Whitespace around the line is ignored.
+func NewFromLine(line string) (*Board, error) {
+	line = strings.TrimSpace(line)
+	match := lineRegex.FindStringSubmatch(line)
+	if match == nil {
+		return nil, fmt.Errorf("invalid one-line format: %s", line)
+	}
+	data := match[3]
+	var subgridWidth, subgridHeight int
+	if match[1] != "" {
+		var err1, err2 error
+		subgridWidth, err1 = strconv.Atoi(match[1])
+		subgridHeight, err2 = strconv.Atoi(match[2])
+		if err1 != nil || err2 != nil || subgridWidth < 1 || subgridHeight < 1 ||
+			subgridWidth > maxLineSize || subgridHeight > maxLineSize || subgridWidth*subgridHeight > maxLineSize {
+			return nil, fmt.Errorf("invalid subgrid size in one-line format: %s", line)
+		}
+	} else {
+		gridSize, ok := lineGridSize(len(data))
+		if !ok {
+			return nil, fmt.Errorf("invalid one-line format length %d, it must be a square of grid size", len(data))
+		}
+		subgridWidth, subgridHeight = lineSubgridSize(gridSize)
+	}
+	board, err := New(subgridWidth, subgridHeight)
+	if err != nil {
+		return nil, fmt.Errorf("error creating board: %w", err)
+	}
+	if len(data) != board.gridSize*board.gridSize {
+		return nil, fmt.Errorf("invalid one-line format length, expected %d, got %d", board.gridSize*board.gridSize, len(data))
+	}
+	for i := 0; i < len(data); i++ {
+		n := lineNumber(data[i])
+		if n > board.gridSize {
+			return nil, fmt.Errorf("invalid number %c at position %d, expected at most %d", data[i], i, board.gridSize)
+		}
+		board.data[i] = uint16(n)
+	}
+	return board, nil
+}
+
+// newFromLineScanner creates board from one-line format in the current line of the scanner.
+// The rest of the input must be empty.
+func newFromLineScanner(scanner *bufio.Scanner) (*Board, error) {
+	board, err := NewFromLine(scanner.Text())
+	if err != nil {
+		return nil, err
+	}
+	for lineNumber := 2; scanner.Scan(); lineNumber++ {
+		if strings.TrimSpace(scanner.Text()) != "" {
+			return nil, fmt.Errorf("unexpected data after one-line board in line %d: %s", lineNumber, scanner.Text())
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return board, nil
+}
+
+// lineGridSize returns grid size of board written in one line with given length.
+func lineGridSize(length int) (int, bool) {
+	for size := 1; size <= maxLineSize; size++ {
+		if size*size == length {
+			return size, true
+		}
+	}
+	return 0, false
+}
+
+// lineSubgridSize returns subgrid size inferred for given grid size (see NewFromLine).
+func lineSubgridSize(gridSize int) (width, height int) {
+	height = 1
+	for h := 2; h*h <= gridSize; h++ {
+		if gridSize%h == 0 {
+			height = h
+		}
+	}
+	return gridSize / height, height
+}
+
+// lineNumber returns number written as character c in one-line format (0 for "." and "0").
+// Characters are not validated (they are matched by lineRegex).
+func lineNumber(c byte) int {
+	switch {
+	case c == '.':
+		return 0
+	case c >= '0' && c <= '9':
+		return int(c - '0')
+	case c >= 'a' && c <= 'z':
+		return int(c-'a') + 10
+	default:
+		return int(c-'A') + 10
+	}
+}
+
+// isLine returns true if line looks like one-line format (so that NewFromSerializedFormat
+// can accept it) - it must have subgrid size prefix or its length must be a square of grid size.
+// Without prefix, 1x1 board is not accepted, because header line with single number
+// (which is invalid) would be taken for it.
+func isLine(line string) bool {
+	line = strings.TrimSpace(line)
+	match := lineRegex.FindStringSubmatch(line)
+	if match == nil {
+		return false
+	}
+	gridSize, ok := lineGridSize(len(match[3]))
+	return match[1] != "" || (ok && gridSize > 1)
+}
+
+// Line returns board in one-line format (see NewFromLine), with "." for empty fields.
+// Subgrid size prefix is added only if it is different than inferred from the length
+// or if the grid is 1x1 (a single character is not recognized as one-line board, see isLine).
+// It returns error for grids larger than 35x35 and for sudoku variants which cannot
+// be written in one line (jigsaw, diagonal, Killer Sudoku cages and other constraints).
+func (b *Board) Line() (string, error) {
+	if b.regions != nil || b.diagonal || len(b.cages) > 0 || len(b.constraints) > 0 {
+		return "", fmt.Errorf("sudoku variants cannot be written in one-line format")
+	}
+	if b.gridSize > maxLineSize {
+		return "", fmt.Errorf("grid size %d is too large for one-line format, it can be at most %d", b.gridSize, maxLineSize)
+	}
+	var s strings.Builder
+	s.Grow(len(b.data) + 8)
+	if width, height := lineSubgridSize(b.gridSize); width != b.subgridWidth || height != b.subgridHeight || b.gridSize == 1 {
+		fmt.Fprintf(&s, "%dx%d:", b.subgridWidth, b.subgridHeight)
+	}
+	for _, n := range b.data {
+		s.WriteByte(lineDigits[n])
+	}
+	return s.String(), nil
+}
+
+// SerializeLine writes board in one-line format (see Line), followed by newline.
+func (b *Board) SerializeLine(writer io.Writer) error {
+	line, err := b.Line()
+	if err != nil {
+		return err
+	}
+	_, err = io.WriteString(writer, line+"\n")
+	return err
+}
@@ -0,0 +1,127 @@
+package board_test
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tomaszmj/sudoku/board"
+)
+
+const line9x9 = "4.....8.5.3..........7......2.....6.....8.4......1.......6.3.7.5..2.....1.4......"
+
+func TestNewFromLine(t *testing.T) {
+	t.Run("9x9 board", func(t *testing.T) {
+		b, err := board.NewFromLine(line9x9)
+		require.NoError(t, err)
+		assert.Equal(t, 9, b.Size())
+		assert.Equal(t, uint16(4), b.Get(0, 0))
+		assert.Equal(t, uint16(0), b.Get(1, 0))
+		assert.Equal(t, uint16(8), b.Get(6, 0))
+		assert.Equal(t, uint16(3), b.Get(1, 1))
+		// zeros are blanks as well
+		b2, err := board.NewFromLine(strings.ReplaceAll(line9x9, ".", "0"))
+		require.NoError(t, err)
+		assert.True(t, b.Equal(b2))
+	})
+
+	t.Run("subgrid size is inferred", func(t *testing.T) {
+		for _, tc := range []struct {
+			size                        int
+			subgridWidth, subgridHeight int
+		}{
+			{1, 1, 1}, {4, 2, 2}, {6, 3, 2}, {8, 4, 2}, {9, 3, 3}, {12, 4, 3}, {16, 4, 4}, {25, 5, 5}, {7, 7, 1},
+		} {
+			b, err := board.NewFromLine(strings.Repeat(".", tc.size*tc.size))
+			require.NoError(t, err)
+			expected, err := board.New(tc.subgridWidth, tc.subgridHeight)
+			require.NoError(t, err)
+			assert.Equal(t, expected.String(), b.String(), "size %d", tc.size)
+		}
+	})
+
+	t.Run("subgrid size prefix", func(t *testing.T) {
+		b, err := board.NewFromLine("3x4:" + strings.Repeat(".", 144))
+		require.NoError(t, err)
+		expected, err := board.New(3, 4)
+		require.NoError(t, err)
+		assert.Equal(t, expected.String(), b.String())
+	})
+
+	t.Run("letters", func(t *testing.T) {
+		b, err := board.NewFromLine("G" + strings.Repeat(".", 254) + "a")
+		require.NoError(t, err)
+		assert.Equal(t, uint16(16), b.Get(0, 0))
+		assert.Equal(t, uint16(10), b.Get(15, 15))
+	})
+
+	t.Run("invalid lines", func(t *testing.T) {
+		for _, line := range []string{
+			"",
+			strings.Repeat(".", 80),
+			line9x9[:80] + "A",                      // number too large
+			line9x9[:80] + "-",                      // invalid character
+			"3x3:" + line9x9[:80],                   // length does not match the prefix
+			"0x3:" + line9x9,                        // invalid subgrid size
+			"7x6:" + line9x9,                        // grid too large for one-line format
+			"4x4:" + strings.Repeat(".", 256) + "1", // too long
+		} {
+			_, err := board.NewFromLine(line)
+			assert.Error(t, err, line)
+		}
+	})
+}
+
+func TestBoardLine(t *testing.T) {
+	t.Run("round trip", func(t *testing.T) {
+		for _, line := range []string{
+			line9x9,
+			"1..4" + strings.Repeat(".", 12),
+			"3x4:" + strings.Repeat(".", 143) + "C",
+			"2x3:" + strings.Repeat(".", 35) + "6",
+			"G" + strings.Repeat(".", 254) + "A",
+			"1x1:1",
+		} {
+			b, err := board.NewFromLine(line)
+			require.NoError(t, err)
+			written, err := b.Line()
+			require.NoError(t, err)
+			assert.Equal(t, line, written)
+		}
+	})
+
+	t.Run("SerializeLine and NewFromSerializedFormat", func(t *testing.T) {
+		b, err := board.NewFromLine(line9x9)
+		require.NoError(t, err)
+		var buf bytes.Buffer
+		require.NoError(t, b.SerializeLine(&buf))
+		assert.Equal(t, line9x9+"\n", buf.String())
+		b2, err := board.NewFromSerializedFormat(&buf)
+		require.NoError(t, err)
+		assert.True(t, b.Equal(b2))
+
+		_, err = board.NewFromSerializedFormat(strings.NewReader(line9x9 + "\n\n" + line9x9))
+		assert.Error(t, err)
+	})
+
+	t.Run("header without colon is not one-line format", func(t *testing.T) {
+		b, err := board.NewFromSerializedFormat(strings.NewReader("2x2\n0 0 0 3\n0 1 0 4\n4 2 3 1\n1 3 4 2\n"))
+		require.NoError(t, err)
+		assert.Equal(t, uint16(3), b.Get(3, 0))
+	})
+
+	t.Run("variants and large grids cannot be written", func(t *testing.T) {
+		b, err := board.New(3, 3)
+		require.NoError(t, err)
+		b.SetDiagonal(true)
+		_, err = b.Line()
+		assert.Error(t, err)
+
+		b, err = board.New(6, 6)
+		require.NoError(t, err)
+		_, err = b.Line()
+		assert.Error(t, err)
+	})
+}
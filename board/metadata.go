@@ -0,0 +1,94 @@
+package board
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+)
+
+// Metadata describes a board read from a puzzle collection (see CollectionReader).
+// It is kept by Copy, but it is ignored by Equal.
+type Metadata struct {
+	Name       string `json:"name,omitempty"`
+	Source     string `json:"source,omitempty"`
+	Difficulty string `json:"difficulty,omitempty"`
+	// Other holds values of other keys (see SetValue).
+	Other map[string]string `json:"other,omitempty"`
+}
+
+const (
+	nameKey       = "name"
+	sourceKey     = "source"
+	difficultyKey = "difficulty"
+)
+
+// IsEmpty returns true if no value is set.
+func (m Metadata) IsEmpty() bool {
+	return m.Name == "" && m.Source == "" && m.Difficulty == "" && len(m.Other) == 0
+}
+
+// SetValue sets value of given key - "name", "source" and "difficulty" (case does not matter)
+// set Name, Source and Difficulty respectively, other keys are stored in Other.
+func (m *Metadata) SetValue(key, value string) {
+	switch strings.ToLower(key) {
+	case nameKey:
+		m.Name = value
+	case sourceKey:
+		m.Source = value
+	case difficultyKey:
+		m.Difficulty = value
+	default:
+		if m.Other == nil {
+			m.Other = make(map[string]string)
+		}
+		m.Other[key] = value
+	}
+}
+
+func (m Metadata) copy() Metadata {
+	if m.Other != nil {
+		other := make(map[string]string, len(m.Other))
+		for k, v := range m.Other {
+			other[k] = v
+		}
+		m.Other = other
+	}
+	return m
+}
+
+// serialize writes metadata lines in format read by CollectionReader, for example:
+// # name: puzzle 1
+// # difficulty: hard
+// Keys in Other are sorted, empty values are skipped.
+func (m Metadata) serialize(writer io.Writer) error {
+	var s strings.Builder
+	write := func(key, value string) {
+		if value != "" {
+			fmt.Fprintf(&s, "%s %s: %s\n", metadataPrefix, key, value)
+		}
+	}
+	write(nameKey, m.Name)
+	write(sourceKey, m.Source)
+	write(difficultyKey, m.Difficulty)
+	keys := make([]string, 0, len(m.Other))
+	for key := range m.Other {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	for _, key := range keys {
+		write(key, m.Other[key])
+	}
+	_, err := io.WriteString(writer, s.String())
+	return err
+}
+
+// Metadata returns metadata of the board (see SetMetadata).
+func (b *Board) Metadata() Metadata {
+	return b.metadata
+}
+
+// SetMetadata sets metadata describing the board.
+func (b *Board) SetMetadata(m Metadata) {
+	b.metadata = m
+}
@@ -0,0 +1,353 @@
+package board
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+)
+
+const (
+	multiGridKeyword = "multigrid"
+	gridKeyword      = "grid"
+)
+
+// MultiGrid is a puzzle made of several grids (boards with the same subgrid size) placed at offsets,
+// which can overlap. Overlapping grids share their fields - a number placed on a shared field belongs
+// to both grids, so it must satisfy rules of both of them. Offsets must be multiples of subgrid
+// width / height, so that grids can share only whole subgrids. Coordinates of the fields
+// are global - field x, y is field x-offset.X, y-offset.Y of each grid containing it.
+// The classic Samurai Sudoku is made of five 9x9 grids (see NewSamurai).
+type MultiGrid struct {
+	grids         []*Board
+	offsets       []Field
+	width, height int
+	subgridWidth  int
+	subgridHeight int
+	gridsOfField  [][]int // indexes of grids containing each field (y*width+x)
+}
+
+// NewMultiGrid creates empty multi-grid puzzle with grids of given subgrid size (see New) placed at given offsets.
+func NewMultiGrid(subgridWidth, subgridHeight int, offsets []Field) (*MultiGrid, error) {
+	if len(offsets) == 0 {
+		return nil, fmt.Errorf("multi-grid puzzle must have at least 1 grid")
+	}
+	m := &MultiGrid{
+		offsets:       append([]Field(nil), offsets...),
+		subgridWidth:  subgridWidth,
+		subgridHeight: subgridHeight,
+	}
+	for i, offset := range offsets {
+		grid, err := New(subgridWidth, subgridHeight)
+		if err != nil {
+			return nil, err
+		}
+		if offset.X < 0 || offset.Y < 0 || offset.X%subgridWidth != 0 || offset.Y%subgridHeight != 0 {
+			return nil, fmt.Errorf("invalid offset (%d, %d) of grid %d, it must be non-negative multiple of subgrid size (%d, %d)",
+				offset.X, offset.Y, i, subgridWidth, subgridHeight)
+		}
+		for j, previous := range offsets[:i] {
+			if previous == offset {
+				return nil, fmt.Errorf("grids %d and %d have the same offset (%d, %d)", j, i, offset.X, offset.Y)
+			}
+		}
+		m.grids = append(m.grids, grid)
+		if offset.X+grid.Size() > m.width {
+			m.width = offset.X + grid.Size()
+		}
+		if offset.Y+grid.Size() > m.height {
+			m.height = offset.Y + grid.Size()
+		}
+	}
+	m.gridsOfField = make([][]int, m.width*m.height)
+	for i, offset := range m.offsets {
+		size := m.grids[i].Size()
+		for y := offset.Y; y < offset.Y+size; y++ {
+			for x := offset.X; x < offset.X+size; x++ {
+				m.gridsOfField[y*m.width+x] = append(m.gridsOfField[y*m.width+x], i)
+			}
+		}
+	}
+	return m, nil
+}
+
+// NewSamurai creates empty Samurai Sudoku - five 9x9 grids, four in the corners and one in the center,
+// which shares one corner subgrid with each of the other grids. Each character below is one subgrid
+// (digit is index of the grid, x marks shared subgrids):
+// 000.111
+// 000.111
+// 00x2x11
+// ..222..
+// 33x2x44
+// 333.444
+// 333.444
+func NewSamurai() *MultiGrid {
+	m, err := NewMultiGrid(3, 3, []Field{{0, 0}, {12, 0}, {6, 6}, {0, 12}, {12, 12}})
+	if err != nil {
+		panic(fmt.Sprintf("error creating samurai: %s", err))
+	}
+	return m
+}
+
+// NewMultiGridFromSerializedFormat creates multi-grid puzzle from data format produced by MultiGrid.Serialize.
+// The first line contains word "multigrid" and subgrid width and height, then for each grid there is
+// line with word "grid" and grid offset, followed by grid data (just like in NewFromSerializedFormat,
+// lines without numbers are ignored). For example, two 4x4 grids sharing one subgrid:
+// multigrid 2 2
+// grid 0 0
+// 1 2 3 4
+// 3 4 1 2
+// 2 1 4 3
+// 4 3 2 1
+// grid 2 2
+// 4 3 1 2
+// 2 1 3 4
+// 1 2 4 3
+// 3 4 2 1
+// Numbers on shared fields must be the same in all grids, unless they are 0 in some of them.
+func NewMultiGridFromSerializedFormat(reader io.Reader) (*MultiGrid, error) {
+	scanner := bufio.NewScanner(reader)
+	if !scanner.Scan() {
+		return nil, fmt.Errorf("error - no data")
+	}
+	if !strings.Contains(scanner.Text(), multiGridKeyword) {
+		return nil, fmt.Errorf("error parsing - expected %q in the first line: %s", multiGridKeyword, scanner.Text())
+	}
+	lines := &numberLinesScanner{scanner: scanner, lineNumber: 1}
+	header, err := lines.parseNumbers()
+	if err != nil {
+		return nil, err
+	}
+	if len(header) != 2 {
+		return nil, fmt.Errorf("error parsing - expected 2 numbers, line: %s", scanner.Text())
+	}
+	subgridWidth, subgridHeight := header[0], header[1]
+	if _, err := New(subgridWidth, subgridHeight); err != nil {
+		return nil, fmt.Errorf("error creating board: %w", err)
+	}
+	gridSize := subgridWidth * subgridHeight
+	var offsets []Field
+	var gridsRows [][][]int
+	for scanner.Scan() {
+		lines.lineNumber++
+		numbers, err := lines.parseNumbers()
+		if err != nil {
+			return nil, err
+		}
+		if len(numbers) == 0 {
+			continue
+		}
+		if !strings.Contains(scanner.Text(), gridKeyword) || len(numbers) != 2 {
+			return nil, fmt.Errorf("expected %q and grid offset in line %d: %s", gridKeyword, lines.lineNumber, scanner.Text())
+		}
+		offsets = append(offsets, Field{numbers[0], numbers[1]})
+		rows, err := lines.scanRows(fmt.Sprintf("grid %d", len(gridsRows)), gridSize, gridSize, nil)
+		if err != nil {
+			return nil, err
+		}
+		gridsRows = append(gridsRows, rows)
+	}
+	m, err := NewMultiGrid(subgridWidth, subgridHeight, offsets)
+	if err != nil {
+		return nil, fmt.Errorf("error creating multi-grid: %w", err)
+	}
+	for i, rows := range gridsRows {
+		for y, row := range rows {
+			for x, n := range row {
+				globalX, globalY := x+offsets[i].X, y+offsets[i].Y
+				if n == 0 {
+					continue
+				}
+				if current := m.Get(globalX, globalY); current != 0 && current != uint16(n) {
+					return nil, fmt.Errorf("grid %d has %d on shared field (%d, %d), but other grid has %d", i, n, globalX, globalY, current)
+				}
+				m.Set(globalX, globalY, uint16(n))
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m *MultiGrid) Copy() *MultiGrid {
+	grids := make([]*Board, 0, len(m.grids))
+	for _, grid := range m.grids {
+		grids = append(grids, grid.Copy())
+	}
+	return &MultiGrid{
+		grids:         grids,
+		offsets:       m.offsets,
+		width:         m.width,
+		height:        m.height,
+		subgridWidth:  m.subgridWidth,
+		subgridHeight: m.subgridHeight,
+		gridsOfField:  m.gridsOfField,
+	}
+}
+
+// Width returns total width of the puzzle (in fields).
+func (m *MultiGrid) Width() int {
+	return m.width
+}
+
+// Height returns total height of the puzzle (in fields).
+func (m *MultiGrid) Height() int {
+	return m.height
+}
+
+// GridSize returns width/height of each grid (see Board.Size).
+func (m *MultiGrid) GridSize() int {
+	return m.grids[0].Size()
+}
+
+// GridsCount returns number of grids in the puzzle.
+func (m *MultiGrid) GridsCount() int {
+	return len(m.grids)
+}
+
+// Grid returns copy of i-th grid and its offset.
+func (m *MultiGrid) Grid(i int) (*Board, Field) {
+	return m.grids[i].Copy(), m.offsets[i]
+}
+
+// Contains returns true if field x, y belongs to any grid.
+func (m *MultiGrid) Contains(x, y int) bool {
+	if x < 0 || x >= m.width || y < 0 || y >= m.height {
+		return false
+	}
+	return len(m.gridsOfField[y*m.width+x]) > 0
+}
+
+// Get returns number on field x, y. It returns 0 for fields which do not belong to any grid.
+func (m *MultiGrid) Get(x, y int) uint16 {
+	if !m.Contains(x, y) {
+		return 0
+	}
+	i := m.gridsOfField[y*m.width+x][0]
+	return m.grids[i].Get(x-m.offsets[i].X, y-m.offsets[i].Y)
+}
+
+// Set puts number on field x, y in all grids containing it. It panics if the field
+// does not belong to any grid.
+func (m *MultiGrid) Set(x, y int, value uint16) {
+	if !m.Contains(x, y) {
+		panic(fmt.Sprintf("cannot set value on field %d, %d, which does not belong to any grid", x, y))
+	}
+	for _, i := range m.gridsOfField[y*m.width+x] {
+		m.grids[i].Set(x-m.offsets[i].X, y-m.offsets[i].Y, value)
+	}
+}
+
+func (m *MultiGrid) Equal(m2 *MultiGrid) bool {
+	if len(m.grids) != len(m2.grids) {
+		return false
+	}
+	for i := range m.grids {
+		if m.offsets[i] != m2.offsets[i] || !m.grids[i].Equal(m2.grids[i]) {
+			return false
+		}
+	}
+	return true
+}
+
+// ForEach calls operation for each field belonging to any grid, row by row.
+func (m *MultiGrid) ForEach(operation func(x, y int, n uint16)) {
+	for y := 0; y < m.height; y++ {
+		for x := 0; x < m.width; x++ {
+			if m.Contains(x, y) {
+				operation(x, y, m.Get(x, y))
+			}
+		}
+	}
+}
+
+// Units returns units of all grids (see Board.Units) with global coordinates of the fields.
+// Subgrids shared by several grids are returned only once. Units are indexed
+// from 0 separately for each kind, in order of grids.
+func (m *MultiGrid) Units() []Unit {
+	var units []Unit
+	indexes := make(map[UnitKind]int)
+	sharedSubgrids := make(map[Field]bool) // by the first field of the subgrid
+	for i, grid := range m.grids {
+		offset := m.offsets[i]
+		for _, unit := range grid.Units() {
+			fields := make([]Field, 0, len(unit.Fields))
+			for _, f := range unit.Fields {
+				fields = append(fields, Field{f.X + offset.X, f.Y + offset.Y})
+			}
+			if unit.Kind == Subgrid {
+				if sharedSubgrids[fields[0]] {
+					continue
+				}
+				sharedSubgrids[fields[0]] = true
+			}
+			units = append(units, Unit{Kind: unit.Kind, Index: indexes[unit.Kind], Fields: fields})
+			indexes[unit.Kind]++
+		}
+	}
+	return units
+}
+
+// CheckRules returns Violations error with every rule violation found in any grid
+// (see Board.CheckRules), with units indexed as in Units, or nil if there are none.
+func (m *MultiGrid) CheckRules() error {
+	var violations Violations
+	for _, unit := range m.Units() {
+		violations = findRepeatedNumbers(violations, unit.Kind, unit.Index, unit.Fields, m.Get, m.GridSize())
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	return violations
+}
+
+func (m *MultiGrid) Serialize(writer io.Writer) error {
+	var s strings.Builder
+	s.WriteString(fmt.Sprintf("%s %d %d\n", multiGridKeyword, m.subgridWidth, m.subgridHeight))
+	for i, grid := range m.grids {
+		s.WriteString(fmt.Sprintf("%s %d %d\n", gridKeyword, m.offsets[i].X, m.offsets[i].Y))
+		s.WriteString(grid.String())
+	}
+	_, err := io.WriteString(writer, s.String())
+	return err
+}
+
+// String draws all grids (see Board.String) at their offsets, so that shared subgrids
+// are drawn only once, for example for 4x4 grids at (0, 0) and (2, 2):
+//
+//	+-----+-----+
+//	| 1 2 | 3 4 |
+//	| 3 4 | 1 2 |
+//	+-----+-----+-----+
+//	| 2 1 | 4 3 | 1 2 |
+//	| 4 3 | 2 1 | 3 4 |
+//	+-----+-----+-----+
+//	      | 1 2 | 4 3 |
+//	      | 3 4 | 2 1 |
+//	      +-----+-----+
+func (m *MultiGrid) String() string {
+	digitLen := len(fmt.Sprint(m.GridSize()))
+	charsPerSubgridX := 2 + m.subgridWidth*(digitLen+1) // "| " and numbers with spaces
+	linesPerSubgridY := m.subgridHeight + 1             // line with "+---" and lines with numbers
+	var canvas [][]byte
+	for i, grid := range m.grids {
+		beginX := m.offsets[i].X / m.subgridWidth * charsPerSubgridX
+		beginY := m.offsets[i].Y / m.subgridHeight * linesPerSubgridY
+		for j, line := range strings.Split(strings.TrimSuffix(grid.String(), "\n"), "\n") {
+			for len(canvas) <= beginY+j {
+				canvas = append(canvas, nil)
+			}
+			row := canvas[beginY+j]
+			for len(row) < beginX+len(line) {
+				row = append(row, ' ')
+			}
+			copy(row[beginX:], line)
+			canvas[beginY+j] = row
+		}
+	}
+	var s strings.Builder
+	for _, row := range canvas {
+		s.Write(row)
+		s.WriteString("\n")
+	}
+	return s.String()
+}
@@ -0,0 +1,140 @@
+package board_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tomaszmj/sudoku/board"
+)
+
+const twoGrids = `multigrid 2 2
+grid 0 0
+1 2 3 4
+3 4 1 2
+2 1 4 3
+4 3 2 1
+grid 2 2
+4 3 1 2
+2 1 3 4
+1 2 4 3
+3 4 2 1
+`
+
+func TestNewMultiGrid(t *testing.T) {
+	t.Run("samurai", func(t *testing.T) {
+		m := board.NewSamurai()
+		assert.Equal(t, 5, m.GridsCount())
+		assert.Equal(t, 9, m.GridSize())
+		assert.Equal(t, 21, m.Width())
+		assert.Equal(t, 21, m.Height())
+		assert.True(t, m.Contains(8, 8))
+		assert.True(t, m.Contains(10, 6))
+		assert.False(t, m.Contains(10, 0))
+		assert.False(t, m.Contains(21, 0))
+		fieldsCount := 0
+		m.ForEach(func(x, y int, n uint16) {
+			fieldsCount++
+		})
+		assert.Equal(t, 5*81-4*9, fieldsCount)
+	})
+	t.Run("no grids", func(t *testing.T) {
+		_, err := board.NewMultiGrid(2, 2, nil)
+		assert.Error(t, err)
+	})
+	t.Run("offset not aligned with subgrids", func(t *testing.T) {
+		_, err := board.NewMultiGrid(2, 2, []board.Field{{0, 0}, {1, 2}})
+		assert.Error(t, err)
+	})
+	t.Run("repeated offset", func(t *testing.T) {
+		_, err := board.NewMultiGrid(2, 2, []board.Field{{0, 0}, {0, 0}})
+		assert.Error(t, err)
+	})
+}
+
+func TestMultiGridSharedFields(t *testing.T) {
+	m, err := board.NewMultiGrid(2, 2, []board.Field{{0, 0}, {2, 2}})
+	require.NoError(t, err)
+	m.Set(3, 3, 4)
+	m.Set(0, 0, 1)
+	m.Set(5, 5, 2)
+	assert.Equal(t, uint16(4), m.Get(3, 3))
+	assert.Equal(t, uint16(0), m.Get(5, 0))
+	grid0, offset0 := m.Grid(0)
+	grid1, offset1 := m.Grid(1)
+	assert.Equal(t, board.Field{X: 0, Y: 0}, offset0)
+	assert.Equal(t, board.Field{X: 2, Y: 2}, offset1)
+	assert.Equal(t, uint16(4), grid0.Get(3, 3))
+	assert.Equal(t, uint16(4), grid1.Get(1, 1))
+	assert.Equal(t, uint16(1), grid0.Get(0, 0))
+	assert.Equal(t, uint16(2), grid1.Get(3, 3))
+	assert.Panics(t, func() { m.Set(5, 0, 1) })
+
+	units := m.Units()
+	assert.Len(t, units, 2*12-1) // shared subgrid is returned once
+	m.Set(5, 3, 4)               // the same number as on shared field (3, 3) in grid 1 row
+	var violations board.Violations
+	require.ErrorAs(t, m.CheckRules(), &violations)
+	assert.Equal(t, board.Violations{
+		{Unit: board.Row, UnitIndex: 5, Number: 4, Fields: []board.Field{{3, 3}, {5, 3}}},
+	}, violations)
+}
+
+func TestMultiGridSerialization(t *testing.T) {
+	t.Run("parse and serialize", func(t *testing.T) {
+		m, err := board.NewMultiGridFromSerializedFormat(strings.NewReader(twoGrids))
+		require.NoError(t, err)
+		assert.NoError(t, m.CheckRules())
+		assert.Equal(t, uint16(4), m.Get(2, 2))
+		assert.Equal(t, uint16(1), m.Get(3, 3))
+		assert.Equal(t, uint16(1), m.Get(5, 5))
+		var serializeOutput strings.Builder
+		require.NoError(t, m.Serialize(&serializeOutput))
+		m2, err := board.NewMultiGridFromSerializedFormat(strings.NewReader(serializeOutput.String()))
+		require.NoError(t, err)
+		assert.True(t, m.Equal(m2))
+		assert.Equal(t, m, m2)
+		assert.True(t, m.Copy().Equal(m2))
+	})
+	t.Run("shared field can be empty in one grid", func(t *testing.T) {
+		m, err := board.NewMultiGridFromSerializedFormat(strings.NewReader(
+			strings.Replace(twoGrids, "grid 2 2\n4 3 1 2\n2 1", "grid 2 2\n0 3 1 2\n2 0", 1)))
+		require.NoError(t, err)
+		assert.Equal(t, uint16(4), m.Get(2, 2))
+		assert.Equal(t, uint16(1), m.Get(3, 3))
+	})
+	t.Run("shared field with different numbers", func(t *testing.T) {
+		_, err := board.NewMultiGridFromSerializedFormat(strings.NewReader(
+			strings.Replace(twoGrids, "grid 2 2\n4 3", "grid 2 2\n3 4", 1)))
+		assert.Error(t, err)
+	})
+	t.Run("missing header", func(t *testing.T) {
+		_, err := board.NewMultiGridFromSerializedFormat(strings.NewReader(strings.TrimPrefix(twoGrids, "multigrid ")))
+		assert.Error(t, err)
+	})
+	t.Run("missing grid line", func(t *testing.T) {
+		_, err := board.NewMultiGridFromSerializedFormat(strings.NewReader(strings.Replace(twoGrids, "grid 2 2\n", "", 1)))
+		assert.Error(t, err)
+	})
+	t.Run("too few grid lines", func(t *testing.T) {
+		_, err := board.NewMultiGridFromSerializedFormat(strings.NewReader(strings.TrimSuffix(twoGrids, "3 4 2 1\n")))
+		assert.Error(t, err)
+	})
+}
+
+func TestMultiGridString(t *testing.T) {
+	m, err := board.NewMultiGridFromSerializedFormat(strings.NewReader(twoGrids))
+	require.NoError(t, err)
+	assert.Equal(t, `+-----+-----+
+| 1 2 | 3 4 |
+| 3 4 | 1 2 |
++-----+-----+-----+
+| 2 1 | 4 3 | 1 2 |
+| 4 3 | 2 1 | 3 4 |
++-----+-----+-----+
+      | 1 2 | 4 3 |
+      | 3 4 | 2 1 |
+      +-----+-----+
+`, m.String())
+}
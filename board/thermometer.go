@@ -0,0 +1,64 @@
+package board
+
+import (
+	"fmt"
+
+	"github.com/tomaszmj/sudoku/set"
+)
+
+// Thermometer is a Constraint - numbers on its fields must strictly increase
+// from the bulb (the first field) to the end of the thermometer.
+type Thermometer []Field
+
+func (t Thermometer) Fields() []Field {
+	return t
+}
+
+func (t Thermometer) Check(b *Board) error {
+	for i, f := range t {
+		n := int(b.Get(f.X, f.Y))
+		if n == 0 {
+			continue
+		}
+		if lowest, highest := t.bounds(b, i); n < lowest || n > highest {
+			return fmt.Errorf("number %d on thermometer field (%d, %d) must be from %d to %d", n, f.X, f.Y, lowest, highest)
+		}
+	}
+	return nil
+}
+
+func (t Thermometer) RemoveCandidates(b *Board, x, y int, candidates *set.Set) {
+	for i, f := range t {
+		if f.X != x || f.Y != y {
+			continue
+		}
+		lowest, highest := t.bounds(b, i)
+		candidates.ForEach(func(n int) bool {
+			if n < lowest || n > highest {
+				candidates.Remove(n)
+			}
+			return false
+		})
+		return
+	}
+}
+
+// bounds returns range of numbers that can be placed on i-th field of the thermometer,
+// given numbers on other fields (number on i-th field is ignored) and number
+// of fields before and after it.
+func (t Thermometer) bounds(b *Board, i int) (int, int) {
+	lowest, highest := i+1, b.Size()-(len(t)-1-i)
+	for j, f := range t {
+		n := int(b.Get(f.X, f.Y))
+		if n == 0 || j == i {
+			continue
+		}
+		if j < i && n+(i-j) > lowest {
+			lowest = n + (i - j)
+		}
+		if j > i && n-(j-i) < highest {
+			highest = n - (j - i)
+		}
+	}
+	return lowest, highest
+}
@@ -0,0 +1,102 @@
+package board
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Violation describes breaking sudoku rules - Number placed
+// more than once in one unit or cage (all its Fields are listed).
+// For cages (Unit is KillerCage) Number can be 0 - it means that numbers
+// in the cage cannot add up to the cage Sum (all cage Fields are listed).
+// For constraints (Unit is ConstraintUnit) Err is returned by Constraint.Check.
+type Violation struct {
+	Unit      UnitKind
+	UnitIndex int
+	Number    uint16
+	Fields    []Field
+	Sum       int   // set only for cage sum violations
+	Err       error // set only for constraint violations
+}
+
+func (v Violation) Error() string {
+	fields := make([]string, 0, len(v.Fields))
+	for _, f := range v.Fields {
+		fields = append(fields, fmt.Sprintf("(%d, %d)", f.X, f.Y))
+	}
+	if v.Err != nil {
+		return fmt.Sprintf("%s %d at %s is broken: %s", v.Unit, v.UnitIndex, strings.Join(fields, ", "), v.Err)
+	}
+	if v.Number == 0 {
+		return fmt.Sprintf("numbers in %s %d at %s do not add up to %d", v.Unit, v.UnitIndex, strings.Join(fields, ", "), v.Sum)
+	}
+	return fmt.Sprintf("number %d is repeated in %s %d at %s", v.Number, v.Unit, v.UnitIndex, strings.Join(fields, ", "))
+}
+
+// Violations is an error listing all rule violations found on the board.
+type Violations []Violation
+
+func (v Violations) Error() string {
+	messages := make([]string, 0, len(v))
+	for _, violation := range v {
+		messages = append(messages, violation.Error())
+	}
+	return strings.Join(messages, "; ")
+}
+
+// CheckRules returns Violations error with every rule violation found on the board
+// (in the order of Units, then cages, then constraints), or nil if there are none. Empty fields are not
+// considered violation, so it can be used for partially filled boards - sum of partially
+// filled cage is violated only if it is already greater than the cage sum.
+func (b *Board) CheckRules() error {
+	var violations Violations
+	for _, unit := range b.Units() {
+		violations = findRepeatedNumbers(violations, unit.Kind, unit.Index, unit.Fields, b.Get, b.gridSize)
+	}
+	for i, cage := range b.cages {
+		violations = findRepeatedNumbers(violations, KillerCage, i, cage.Fields, b.Get, b.gridSize)
+		if b.cageSumBroken(cage) {
+			violations = append(violations, Violation{
+				Unit:      KillerCage,
+				UnitIndex: i,
+				Fields:    append([]Field(nil), cage.Fields...),
+				Sum:       cage.Sum,
+			})
+		}
+	}
+	for i, c := range b.constraints {
+		if err := c.Check(b); err != nil {
+			violations = append(violations, Violation{
+				Unit:      ConstraintUnit,
+				UnitIndex: i,
+				Fields:    append([]Field(nil), c.Fields()...),
+				Err:       err,
+			})
+		}
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+	return violations
+}
+
+// findRepeatedNumbers appends to violations numbers (from 1 to maxNumber) repeated in given fields.
+func findRepeatedNumbers(violations Violations, kind UnitKind, index int, unitFields []Field, get func(x, y int) uint16, maxNumber int) Violations {
+	fieldsWithNumber := make([][]Field, maxNumber+1)
+	for _, f := range unitFields {
+		n := get(f.X, f.Y)
+		fieldsWithNumber[n] = append(fieldsWithNumber[n], f)
+	}
+	for n := 1; n <= maxNumber; n++ {
+		if len(fieldsWithNumber[n]) < 2 {
+			continue
+		}
+		violations = append(violations, Violation{
+			Unit:      kind,
+			UnitIndex: index,
+			Number:    uint16(n),
+			Fields:    fieldsWithNumber[n],
+		})
+	}
+	return violations
+}
@@ -0,0 +1,38 @@
+package board_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tomaszmj/sudoku/board"
+)
+
+func TestBoardCheckRules(t *testing.T) {
+	t.Run("valid partially filled board", func(t *testing.T) {
+		b, err := board.NewFromSerializedFormat(strings.NewReader("2 2\n0 0 0 3\n0 1 0 4\n4 2 3 1\n1 3 4 2\n"))
+		require.NoError(t, err)
+		assert.NoError(t, b.CheckRules())
+	})
+
+	t.Run("all violations are reported", func(t *testing.T) {
+		b, err := board.NewFromSerializedFormat(strings.NewReader("2 2\n1 0 0 1\n0 1 0 4\n4 2 3 1\n1 3 4 2\n"))
+		require.NoError(t, err)
+		err = b.CheckRules()
+		require.Error(t, err)
+		var violations board.Violations
+		require.True(t, errors.As(err, &violations))
+		assert.Equal(t, board.Violations{
+			{Unit: board.Row, UnitIndex: 0, Number: 1, Fields: []board.Field{{0, 0}, {3, 0}}},
+			{Unit: board.Column, UnitIndex: 0, Number: 1, Fields: []board.Field{{0, 0}, {0, 3}}},
+			{Unit: board.Column, UnitIndex: 3, Number: 1, Fields: []board.Field{{3, 0}, {3, 2}}},
+			{Unit: board.Subgrid, UnitIndex: 0, Number: 1, Fields: []board.Field{{0, 0}, {1, 1}}},
+		}, violations)
+		assert.Equal(t, "number 1 is repeated in row 0 at (0, 0), (3, 0); "+
+			"number 1 is repeated in column 0 at (0, 0), (0, 3); "+
+			"number 1 is repeated in column 3 at (3, 0), (3, 2); "+
+			"number 1 is repeated in subgrid 0 at (0, 0), (1, 1)", err.Error())
+	})
+}
@@ -1,8 +1,13 @@
 package main
 
 import (
+	"bytes"
+	"context"
+	"errors"
 	"fmt"
+	"io"
 	"os"
+	"strings"
 
 	"github.com/tomaszmj/sudoku/board"
 	"github.com/tomaszmj/sudoku/solver"
@@ -13,30 +18,135 @@ func main() {
 		fmt.Println("exactly 1 argument required (path to board)")
 		return
 	}
-	file, err := os.Open(os.Args[1])
+	data, err := os.ReadFile(os.Args[1])
 	if err != nil {
-		fmt.Printf("error opening file: %s\n", err)
+		fmt.Printf("error reading file: %s\n", err)
 		return
 	}
-	board1, err := board.NewFromSerializedFormat(file)
-	if err2 := file.Close(); err2 != nil {
-		fmt.Printf("error closing file: %s\n", err2)
+	if bytes.HasPrefix(data, []byte("multigrid")) {
+		solveMultiGrid(data)
+		return
 	}
+	board1, err := board.NewFromSerializedFormat(bytes.NewReader(data))
 	if err != nil {
+		// the file may be a collection of many boards
+		if solveCollection(data) {
+			return
+		}
 		fmt.Printf("error creating board from file %s: %s\n", os.Args[1], err)
 		return
 	}
 	fmt.Printf("input:\n%s\n", board1)
-	solver := solver.NewSmartBarcktrack()
-	solver.Reset(board1)
-	solution := solver.NextSolution()
+	if cages := board1.Cages(); len(cages) > 0 {
+		fmt.Println("cages:")
+		for _, cage := range cages {
+			fmt.Println(cage)
+		}
+		fmt.Println()
+	}
+	if err := board1.CheckRules(); err != nil {
+		printViolations(err)
+		return
+	}
+	s := solver.NewSmartBarcktrack()
+	s.Reset(board1)
+	solution := s.NextSolution()
 	if solution == nil {
 		fmt.Println("no solution")
-	} else {
-		fmt.Printf("solution:\n%s\n", solution)
-		solution2 := solver.NextSolution()
-		if solution2 != nil {
-			fmt.Println("there are more solutions to this board (only 1 has been shown)")
+		return
+	}
+	fmt.Printf("solution:\n%s\n", solution)
+	if solver.CountSolutions(board1, 2) > 1 {
+		fmt.Println("there are more solutions to this board (only 1 has been shown)")
+	}
+}
+
+// solveCollection prints solution of each board from collection (see board.CollectionReader)
+// in one line. It returns false if data is not a collection of many boards.
+func solveCollection(data []byte) bool {
+	reader := board.NewCollectionReader(bytes.NewReader(data))
+	var boards []*board.Board
+	var errs []error
+	for {
+		b, err := reader.Next()
+		if err == io.EOF {
+			break
+		}
+		var recordErr *board.RecordError
+		if err != nil && !errors.As(err, &recordErr) {
+			fmt.Printf("error reading collection from file %s: %s\n", os.Args[1], err)
+			return true
+		}
+		boards = append(boards, b)
+		errs = append(errs, err)
+	}
+	if len(boards) < 2 {
+		return false
+	}
+	s := solver.NewSmartBarcktrack()
+	for i, b := range boards {
+		name := fmt.Sprintf("%d", i+1)
+		if errs[i] != nil {
+			fmt.Printf("%s: %s\n", name, errs[i])
+			continue
+		}
+		if b.Metadata().Name != "" {
+			name += " " + b.Metadata().Name
+		}
+		s.Reset(b)
+		solution, err := solver.NextSolutionContext(context.Background(), s)
+		if err != nil {
+			fmt.Printf("%s: board breaks sudoku rules: %s\n", name, err)
+			continue
+		}
+		if solution == nil {
+			fmt.Printf("%s: no solution\n", name)
+			continue
+		}
+		if solver.CountSolutions(b, 2) > 1 {
+			name += " (there are more solutions)"
 		}
+		line, err := solution.Line()
+		if err != nil {
+			line = "\n" + strings.TrimSuffix(solution.String(), "\n") // variants are printed as ASCII art
+		}
+		fmt.Printf("%s: %s\n", name, line)
+	}
+	return true
+}
+
+func solveMultiGrid(data []byte) {
+	multiGrid, err := board.NewMultiGridFromSerializedFormat(bytes.NewReader(data))
+	if err != nil {
+		fmt.Printf("error creating multi-grid puzzle from file %s: %s\n", os.Args[1], err)
+		return
+	}
+	fmt.Printf("input:\n%s\n", multiGrid)
+	if err := multiGrid.CheckRules(); err != nil {
+		printViolations(err)
+		return
+	}
+	s := solver.NewMultiGrid()
+	s.Reset(multiGrid)
+	solution := s.NextSolution()
+	if solution == nil {
+		fmt.Println("no solution")
+		return
+	}
+	fmt.Printf("solution:\n%s\n", solution)
+	if solver.CountMultiGridSolutions(multiGrid, 2) > 1 {
+		fmt.Println("there are more solutions to this board (only 1 has been shown)")
+	}
+}
+
+func printViolations(err error) {
+	var violations board.Violations
+	if !errors.As(err, &violations) {
+		fmt.Printf("board breaks sudoku rules: %s\n", err)
+		return
+	}
+	fmt.Println("board breaks sudoku rules:")
+	for _, violation := range violations {
+		fmt.Println(violation)
 	}
 }
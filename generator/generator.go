@@ -0,0 +1,180 @@
+// Package generator creates random sudoku puzzles with exactly one solution.
+package generator
+
+import (
+	"fmt"
+	"math/rand"
+
+	"github.com/tomaszmj/sudoku/board"
+	"github.com/tomaszmj/sudoku/rating"
+	"github.com/tomaszmj/sudoku/solver"
+)
+
+const defaultMaxAttempts = 100
+
+type Generator struct {
+	rand   *rand.Rand
+	solver solver.Solver
+}
+
+// New creates Generator which uses r as a source of randomness and s to
+// check whether puzzle has exactly one solution.
+func New(r *rand.Rand, s solver.Solver) *Generator {
+	return &Generator{
+		rand:   r,
+		solver: s,
+	}
+}
+
+// CompleteGrid returns random, completely filled board with given subgrid width and height.
+// First row is filled with random permutation of numbers and the rest of the board
+// is filled by solver. Then rows and columns are shuffled in a way that does not break
+// sudoku rules (rows within the same band of subgrids, then whole bands, the same for columns).
+func (g *Generator) CompleteGrid(subgridWidth, subgridHeight int) (*board.Board, error) {
+	b, err := board.New(subgridWidth, subgridHeight)
+	if err != nil {
+		return nil, err
+	}
+	size := b.Size()
+	for x, n := range g.rand.Perm(size) {
+		b.Set(x, 0, uint16(n+1))
+	}
+	g.solver.Reset(b)
+	solution := g.solver.NextSolution()
+	if solution == nil {
+		return nil, fmt.Errorf("solver has not found any solution for board:\n%s", b)
+	}
+	rows := g.shuffledLines(size, subgridHeight)
+	columns := g.shuffledLines(size, subgridWidth)
+	solution.ForEach(func(x, y int, n uint16) {
+		b.Set(columns[x], rows[y], n)
+	})
+	return b, nil
+}
+
+// Generate returns random puzzle with given subgrid width and height, which has
+// exactly one solution, and the solution itself. Numbers are removed from complete
+// grid in random order as long as solution remains unique, so no number can
+// be removed from the puzzle without introducing another solution.
+func (g *Generator) Generate(subgridWidth, subgridHeight int) (puzzle, solution *board.Board, err error) {
+	solution, err = g.CompleteGrid(subgridWidth, subgridHeight)
+	if err != nil {
+		return nil, nil, err
+	}
+	puzzle, err = g.removeNumbers(solution, NoSymmetry, rating.Diabolical)
+	if err != nil {
+		return nil, nil, err
+	}
+	return puzzle, solution, nil
+}
+
+// Options describe puzzle to be created by GenerateWithOptions.
+type Options struct {
+	SubgridWidth  int
+	SubgridHeight int
+	Symmetry      Symmetry
+	// MinTier and MaxTier is the requested difficulty band (inclusive, see rating.Rate).
+	// If MinTier is 0, there is no lower bound, if MaxTier is 0, there is no upper bound
+	// (zero value is not a valid rating.Tier).
+	MinTier rating.Tier
+	MaxTier rating.Tier
+	// MaxAttempts limits number of complete grids from which puzzle is created
+	// before GenerateWithOptions gives up. If it is 0, default limit is used.
+	MaxAttempts int
+}
+
+// Result is a puzzle created by GenerateWithOptions.
+type Result struct {
+	Puzzle   *board.Board
+	Solution *board.Board
+	Rating   rating.Rating
+}
+
+// GenerateWithOptions works like Generate, but numbers are removed in groups
+// of fields that are mapped onto each other by opts.Symmetry and the puzzle
+// difficulty is kept within [opts.MinTier, opts.MaxTier] band.
+// Numbers whose removal would make the puzzle harder than opts.MaxTier are kept.
+// If the puzzle is still easier than opts.MinTier when no more numbers can be removed,
+// it is discarded and the whole process is repeated with a new complete grid.
+func (g *Generator) GenerateWithOptions(opts Options) (Result, error) {
+	if opts.MaxTier == 0 {
+		opts.MaxTier = rating.Diabolical
+	}
+	if opts.MinTier == 0 {
+		opts.MinTier = rating.Easy
+	}
+	if opts.MinTier > opts.MaxTier {
+		return Result{}, fmt.Errorf("invalid difficulty band - min tier %s is above max tier %s", opts.MinTier, opts.MaxTier)
+	}
+	maxAttempts := opts.MaxAttempts
+	if maxAttempts == 0 {
+		maxAttempts = defaultMaxAttempts
+	}
+	for attempt := 0; attempt < maxAttempts; attempt++ {
+		solution, err := g.CompleteGrid(opts.SubgridWidth, opts.SubgridHeight)
+		if err != nil {
+			return Result{}, err
+		}
+		puzzle, err := g.removeNumbers(solution, opts.Symmetry, opts.MaxTier)
+		if err != nil {
+			return Result{}, err
+		}
+		r, err := rating.Rate(puzzle)
+		if err != nil {
+			return Result{}, fmt.Errorf("error rating generated puzzle: %w", err)
+		}
+		if r.Tier >= opts.MinTier && r.Tier <= opts.MaxTier {
+			return Result{Puzzle: puzzle, Solution: solution, Rating: r}, nil
+		}
+	}
+	return Result{}, fmt.Errorf("could not generate puzzle rated %s-%s in %d attempts", opts.MinTier, opts.MaxTier, maxAttempts)
+}
+
+// removeNumbers removes numbers from solution copy in random order, keeping solution unique,
+// layout symmetric and difficulty not higher than maxTier.
+func (g *Generator) removeNumbers(solution *board.Board, symmetry Symmetry, maxTier rating.Tier) (*board.Board, error) {
+	puzzle := solution.Copy()
+	size := puzzle.Size()
+	for _, offset := range g.rand.Perm(size * size) {
+		x, y := offset%size, offset/size
+		if puzzle.Get(x, y) == 0 {
+			continue // already removed as a part of other field's orbit
+		}
+		orbit := symmetry.orbit(x, y, size)
+		for _, f := range orbit {
+			puzzle.Set(f.X, f.Y, 0)
+		}
+		keep := !g.hasUniqueSolution(puzzle)
+		if !keep && maxTier < rating.Diabolical {
+			r, err := rating.Rate(puzzle)
+			if err != nil {
+				return nil, fmt.Errorf("error rating puzzle: %w", err)
+			}
+			keep = r.Tier > maxTier
+		}
+		if keep {
+			for _, f := range orbit {
+				puzzle.Set(f.X, f.Y, solution.Get(f.X, f.Y))
+			}
+		}
+	}
+	return puzzle, nil
+}
+
+func (g *Generator) hasUniqueSolution(b *board.Board) bool {
+	g.solver.Reset(b)
+	return g.solver.NextSolution() != nil && g.solver.NextSolution() == nil
+}
+
+// shuffledLines returns random permutation of lines (rows or columns) 0..size-1,
+// in which lines are moved only within their band of lineGroupSize lines
+// and whole bands are moved together.
+func (g *Generator) shuffledLines(size, lineGroupSize int) []int {
+	lines := make([]int, 0, size)
+	for _, group := range g.rand.Perm(size / lineGroupSize) {
+		for _, line := range g.rand.Perm(lineGroupSize) {
+			lines = append(lines, group*lineGroupSize+line)
+		}
+	}
+	return lines
+}
@@ -0,0 +1,183 @@
+package generator_test
+
+import (
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tomaszmj/sudoku/board"
+	"github.com/tomaszmj/sudoku/generator"
+	"github.com/tomaszmj/sudoku/rating"
+	"github.com/tomaszmj/sudoku/solver"
+)
+
+var sizes = []struct {
+	name                        string
+	subgridWidth, subgridHeight int
+}{
+	{"4x4", 2, 2},
+	{"6x6", 3, 2},
+	{"9x9", 3, 3},
+	{"12x12", 3, 4},
+}
+
+func TestCompleteGrid(t *testing.T) {
+	g := generator.New(rand.New(rand.NewSource(1)), solver.NewSmartBarcktrack())
+	for _, size := range sizes {
+		t.Run(size.name, func(t *testing.T) {
+			grid, err := g.CompleteGrid(size.subgridWidth, size.subgridHeight)
+			require.NoError(t, err)
+			assertSolved(t, grid)
+		})
+	}
+
+	t.Run("grids are random", func(t *testing.T) {
+		grid1, err := g.CompleteGrid(3, 3)
+		require.NoError(t, err)
+		grid2, err := g.CompleteGrid(3, 3)
+		require.NoError(t, err)
+		assert.False(t, grid1.Equal(grid2))
+	})
+
+	t.Run("invalid size", func(t *testing.T) {
+		_, err := g.CompleteGrid(0, 3)
+		assert.Error(t, err)
+	})
+}
+
+func TestGenerate(t *testing.T) {
+	s := solver.NewSmartBarcktrack()
+	g := generator.New(rand.New(rand.NewSource(2)), solver.NewSmartBarcktrack())
+	for _, size := range sizes {
+		t.Run(size.name, func(t *testing.T) {
+			puzzle, solution, err := g.Generate(size.subgridWidth, size.subgridHeight)
+			require.NoError(t, err)
+			assertSolved(t, solution)
+			puzzle.ForEach(func(x, y int, n uint16) {
+				if n != 0 {
+					assert.Equal(t, solution.Get(x, y), n)
+				}
+			})
+			s.Reset(puzzle)
+			found := s.NextSolution()
+			require.NotNil(t, found)
+			assert.True(t, solution.Equal(found))
+			assert.Nil(t, s.NextSolution())
+		})
+	}
+
+	t.Run("no number can be removed", func(t *testing.T) {
+		puzzle, _, err := g.Generate(3, 2)
+		require.NoError(t, err)
+		puzzle.ForEach(func(x, y int, n uint16) {
+			if n == 0 {
+				return
+			}
+			b := puzzle.Copy()
+			b.Set(x, y, 0)
+			s.Reset(b)
+			require.NotNil(t, s.NextSolution())
+			assert.NotNil(t, s.NextSolution(), "number at %d, %d can be removed", x, y)
+		})
+	})
+}
+
+func assertSolved(t *testing.T, b *board.Board) {
+	for _, unit := range b.Units() {
+		found := make(map[uint16]bool)
+		for _, f := range unit.Fields {
+			n := b.Get(f.X, f.Y)
+			assert.NotEqual(t, uint16(0), n)
+			assert.False(t, found[n], "%d repeated in %s %d", n, unit.Kind, unit.Index)
+			found[n] = true
+		}
+	}
+}
+
+func TestGenerateWithOptions(t *testing.T) {
+	g := generator.New(rand.New(rand.NewSource(3)), solver.NewSmartBarcktrack())
+
+	symmetries := map[generator.Symmetry]func(x, y, last int) (int, int){
+		generator.Rotational: func(x, y, last int) (int, int) { return last - x, last - y },
+		generator.Mirror:     func(x, y, last int) (int, int) { return last - x, y },
+		generator.Diagonal:   func(x, y, last int) (int, int) { return y, x },
+		generator.Dihedral:   func(x, y, last int) (int, int) { return y, last - x },
+	}
+	for symmetry, transform := range symmetries {
+		t.Run(symmetry.String()+" symmetry", func(t *testing.T) {
+			result, err := g.GenerateWithOptions(generator.Options{
+				SubgridWidth:  3,
+				SubgridHeight: 3,
+				Symmetry:      symmetry,
+				MaxTier:       rating.Diabolical,
+			})
+			require.NoError(t, err)
+			last := result.Puzzle.Size() - 1
+			result.Puzzle.ForEach(func(x, y int, n uint16) {
+				x2, y2 := transform(x, y, last)
+				assert.Equal(t, n == 0, result.Puzzle.Get(x2, y2) == 0, "fields %d, %d and %d, %d", x, y, x2, y2)
+			})
+		})
+	}
+
+	t.Run("symmetry on board with rectangular subgrids", func(t *testing.T) {
+		result, err := g.GenerateWithOptions(generator.Options{
+			SubgridWidth:  3,
+			SubgridHeight: 2,
+			Symmetry:      generator.Dihedral,
+			MaxTier:       rating.Diabolical,
+		})
+		require.NoError(t, err)
+		assertSolved(t, result.Solution)
+		// each orbit of Dihedral symmetry must be entirely filled or entirely empty
+		last := result.Puzzle.Size() - 1
+		result.Puzzle.ForEach(func(x, y int, n uint16) {
+			orbit := [][2]int{
+				{last - x, y}, {x, last - y}, {last - x, last - y},
+				{y, x}, {last - y, x}, {y, last - x}, {last - y, last - x},
+			}
+			for _, f := range orbit {
+				assert.Equal(t, n == 0, result.Puzzle.Get(f[0], f[1]) == 0, "fields %d, %d and %d, %d", x, y, f[0], f[1])
+			}
+		})
+	})
+
+	for _, tier := range []rating.Tier{rating.Easy, rating.Medium} {
+		t.Run(tier.String()+" puzzle", func(t *testing.T) {
+			result, err := g.GenerateWithOptions(generator.Options{
+				SubgridWidth:  3,
+				SubgridHeight: 3,
+				Symmetry:      generator.Rotational,
+				MinTier:       tier,
+				MaxTier:       tier,
+			})
+			require.NoError(t, err)
+			assert.Equal(t, tier, result.Rating.Tier)
+			r, err := rating.Rate(result.Puzzle)
+			require.NoError(t, err)
+			assert.Equal(t, result.Rating, r)
+		})
+	}
+
+	t.Run("only lower bound of difficulty", func(t *testing.T) {
+		// MaxTier is not set, so there is no upper bound
+		result, err := g.GenerateWithOptions(generator.Options{
+			SubgridWidth:  3,
+			SubgridHeight: 3,
+			MinTier:       rating.Hard,
+		})
+		require.NoError(t, err)
+		assert.GreaterOrEqual(t, int(result.Rating.Tier), int(rating.Hard))
+	})
+
+	t.Run("invalid difficulty band", func(t *testing.T) {
+		_, err := g.GenerateWithOptions(generator.Options{
+			SubgridWidth:  3,
+			SubgridHeight: 3,
+			MinTier:       rating.Hard,
+			MaxTier:       rating.Easy,
+		})
+		assert.Error(t, err)
+	})
+}
@@ -0,0 +1,78 @@
+package generator
+
+import (
+	"fmt"
+
+	"github.com/tomaszmj/sudoku/board"
+)
+
+// Symmetry describes symmetry of clues (non-empty fields) layout in generated puzzle.
+type Symmetry int
+
+const (
+	NoSymmetry Symmetry = iota
+	// Rotational means that layout does not change after rotating the board by 180 degrees.
+	Rotational
+	// Mirror means that layout does not change after reflecting the board over vertical axis.
+	Mirror
+	// Diagonal means that layout does not change after reflecting the board over main diagonal.
+	Diagonal
+	// Dihedral means that layout does not change after any rotation by multiple of 90 degrees or any reflection.
+	Dihedral
+)
+
+func (s Symmetry) String() string {
+	switch s {
+	case NoSymmetry:
+		return "none"
+	case Rotational:
+		return "rotational"
+	case Mirror:
+		return "mirror"
+	case Diagonal:
+		return "diagonal"
+	case Dihedral:
+		return "dihedral"
+	}
+	return fmt.Sprintf("Symmetry(%d)", int(s))
+}
+
+// orbit returns all fields that are mapped onto field x, y by symmetry s
+// (including x, y itself) on a board with given size. Each field is returned once.
+func (s Symmetry) orbit(x, y, size int) []board.Field {
+	last := size - 1
+	var fields []board.Field
+	add := func(x, y int) {
+		field := board.Field{X: x, Y: y}
+		if !containsField(fields, field) {
+			fields = append(fields, field)
+		}
+	}
+	add(x, y)
+	switch s {
+	case Rotational:
+		add(last-x, last-y)
+	case Mirror:
+		add(last-x, y)
+	case Diagonal:
+		add(y, x)
+	case Dihedral:
+		add(last-x, y)
+		add(x, last-y)
+		add(last-x, last-y)
+		add(y, x)
+		add(last-y, x)
+		add(y, last-x)
+		add(last-y, last-x)
+	}
+	return fields
+}
+
+func containsField(fields []board.Field, field board.Field) bool {
+	for _, f := range fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,22 @@
+// Package testutil contains helpers shared by tests of other packages.
+package testutil
+
+import (
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"github.com/tomaszmj/sudoku/board"
+)
+
+// ReadBoard reads board in format of board.NewFromSerializedFormat from file,
+// the test fails immediately if it cannot be read.
+func ReadBoard(t *testing.T, path string) *board.Board {
+	t.Helper()
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	b, err := board.NewFromSerializedFormat(file)
+	require.NoError(t, err)
+	return b
+}
@@ -0,0 +1,123 @@
+// Package rating provides objective difficulty rating of sudoku puzzles.
+// The rating is based on techniques that human players need to solve the puzzle
+// (see solver.Logical). Puzzles that cannot be solved with logic alone are
+// rated using statistics of backtracking search (see solver.Stats).
+package rating
+
+import (
+	"fmt"
+
+	"github.com/tomaszmj/sudoku/board"
+	"github.com/tomaszmj/sudoku/solver"
+)
+
+// Tier is a named difficulty level. Zero value is not a valid tier, so that
+// it can mean "not set" (e.g. in generator.Options).
+type Tier int
+
+const (
+	Easy Tier = iota + 1
+	Medium
+	Hard
+	Expert
+	Diabolical
+)
+
+func (t Tier) String() string {
+	switch t {
+	case Easy:
+		return "easy"
+	case Medium:
+		return "medium"
+	case Hard:
+		return "hard"
+	case Expert:
+		return "expert"
+	case Diabolical:
+		return "diabolical"
+	}
+	return fmt.Sprintf("Tier(%d)", int(t))
+}
+
+// Rating is a result of Rate.
+type Rating struct {
+	// Score is a difficulty score - the higher, the more difficult the puzzle is.
+	// It is meant to compare and sort puzzles of the same size.
+	Score int
+	Tier  Tier
+	// Techniques counts how many times each technique was used by logical solver.
+	Techniques map[solver.Technique]int
+	// SolvedWithLogic is false if logical solver got stuck and backtracking was needed.
+	SolvedWithLogic bool
+	// Backtracking holds statistics of backtracking search (only if SolvedWithLogic is false).
+	Backtracking solver.Stats
+}
+
+// techniqueScores is score added each time given technique is used.
+var techniqueScores = map[solver.Technique]int{
+	solver.NakedSingle:      1,
+	solver.HiddenSingle:     2,
+	solver.PointingPair:     10,
+	solver.BoxLineReduction: 10,
+	solver.NakedPair:        20,
+	solver.HiddenPair:       30,
+	solver.NakedTriple:      40,
+	solver.HiddenTriple:     50,
+}
+
+// techniqueTiers is the tier of a puzzle, for which given technique is the most difficult one needed.
+var techniqueTiers = map[solver.Technique]Tier{
+	solver.NakedSingle:      Easy,
+	solver.HiddenSingle:     Easy,
+	solver.PointingPair:     Medium,
+	solver.BoxLineReduction: Medium,
+	solver.NakedPair:        Hard,
+	solver.HiddenPair:       Hard,
+	solver.NakedTriple:      Expert,
+	solver.HiddenTriple:     Expert,
+}
+
+const (
+	// logicFailedScore is added to score of puzzles which cannot be solved with logic alone,
+	// so that they are always rated higher than puzzles solved with logic.
+	logicFailedScore = 1000
+	guessScore       = 20
+	backtrackScore   = 10
+)
+
+// Rate returns difficulty rating of given board. It returns error if
+// the board does not have exactly one solution.
+func Rate(b *board.Board) (Rating, error) {
+	if err := b.CheckRules(); err != nil {
+		return Rating{}, fmt.Errorf("invalid board: %w", err)
+	}
+	rating := Rating{Tier: Easy, Techniques: make(map[solver.Technique]int)}
+	logical := solver.NewLogical()
+	logical.Reset(b)
+	solution := logical.NextSolution()
+	for _, step := range logical.Steps() {
+		rating.Techniques[step.Technique]++
+		rating.Score += techniqueScores[step.Technique]
+		if tier := techniqueTiers[step.Technique]; tier > rating.Tier {
+			rating.Tier = tier
+		}
+	}
+	if solution != nil {
+		rating.SolvedWithLogic = true
+		return rating, nil
+	}
+
+	backtrack := solver.NewSmartBarcktrack()
+	backtrack.Reset(b)
+	if backtrack.NextSolution() == nil {
+		return Rating{}, fmt.Errorf("board has no solution")
+	}
+	stats := backtrack.(solver.StatsReporter).Stats()
+	if backtrack.NextSolution() != nil {
+		return Rating{}, fmt.Errorf("board has more than one solution")
+	}
+	rating.Backtracking = stats
+	rating.Tier = Diabolical
+	rating.Score += logicFailedScore + stats.Guesses*guessScore + stats.Backtracks*backtrackScore
+	return rating, nil
+}
@@ -0,0 +1,64 @@
+package rating_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tomaszmj/sudoku/board"
+	"github.com/tomaszmj/sudoku/internal/testutil"
+	"github.com/tomaszmj/sudoku/rating"
+	"github.com/tomaszmj/sudoku/solver"
+)
+
+func TestRate(t *testing.T) {
+	easy, err := rating.Rate(testutil.ReadBoard(t, "../cmd/boards/easy9x9.txt"))
+	require.NoError(t, err)
+	difficult, err := rating.Rate(testutil.ReadBoard(t, "../cmd/boards/difficult9x9.txt"))
+	require.NoError(t, err)
+	veryDifficult, err := rating.Rate(testutil.ReadBoard(t, "../cmd/boards/very_difficult_9x9.txt"))
+	require.NoError(t, err)
+
+	t.Run("easy puzzle needs only singles", func(t *testing.T) {
+		assert.Equal(t, rating.Easy, easy.Tier)
+		assert.True(t, easy.SolvedWithLogic)
+		for technique := range easy.Techniques {
+			assert.Contains(t, []solver.Technique{solver.NakedSingle, solver.HiddenSingle}, technique)
+		}
+	})
+
+	t.Run("difficult puzzles need backtracking", func(t *testing.T) {
+		assert.Equal(t, rating.Diabolical, difficult.Tier)
+		assert.False(t, difficult.SolvedWithLogic)
+		assert.Greater(t, difficult.Backtracking.Guesses, 0)
+		assert.Equal(t, rating.Diabolical, veryDifficult.Tier)
+	})
+
+	t.Run("score follows hand-made labels", func(t *testing.T) {
+		assert.Less(t, easy.Score, difficult.Score)
+		assert.Less(t, difficult.Score, veryDifficult.Score)
+	})
+
+	t.Run("puzzle of other size", func(t *testing.T) {
+		r, err := rating.Rate(testutil.ReadBoard(t, "../cmd/boards/6x6.txt"))
+		require.NoError(t, err)
+		assert.True(t, r.SolvedWithLogic)
+		assert.Greater(t, r.Score, 0)
+	})
+
+	t.Run("puzzle with many solutions", func(t *testing.T) {
+		b, err := board.New(2, 2)
+		require.NoError(t, err)
+		_, err = rating.Rate(b)
+		assert.Error(t, err)
+	})
+
+	t.Run("puzzle without solution", func(t *testing.T) {
+		b, err := board.New(2, 2)
+		require.NoError(t, err)
+		b.Set(0, 0, 1)
+		b.Set(1, 1, 1)
+		_, err = rating.Rate(b)
+		assert.Error(t, err)
+	})
+}
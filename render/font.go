@@ -0,0 +1,118 @@
+package render
+
+import (
+	"fmt"
+	"image"
+	"image/color"
+	"strings"
+	"unicode"
+)
+
+const (
+	glyphWidth  = 5
+	glyphHeight = 7
+)
+
+// glyphs is 5x7 bitmap font used by PNG - each row is a bit mask, the highest of 5 bits
+// is the leftmost pixel. Only digits and letters are needed (lowercase letters are drawn
+// as uppercase), other characters are drawn as "?".
+var glyphs = map[rune][glyphHeight]uint8{
+	'0': {0b01110, 0b10001, 0b10011, 0b10101, 0b11001, 0b10001, 0b01110},
+	'1': {0b00100, 0b01100, 0b00100, 0b00100, 0b00100, 0b00100, 0b01110},
+	'2': {0b01110, 0b10001, 0b00001, 0b00010, 0b00100, 0b01000, 0b11111},
+	'3': {0b11111, 0b00010, 0b00100, 0b00010, 0b00001, 0b10001, 0b01110},
+	'4': {0b00010, 0b00110, 0b01010, 0b10010, 0b11111, 0b00010, 0b00010},
+	'5': {0b11111, 0b10000, 0b11110, 0b00001, 0b00001, 0b10001, 0b01110},
+	'6': {0b00110, 0b01000, 0b10000, 0b11110, 0b10001, 0b10001, 0b01110},
+	'7': {0b11111, 0b00001, 0b00010, 0b00100, 0b01000, 0b01000, 0b01000},
+	'8': {0b01110, 0b10001, 0b10001, 0b01110, 0b10001, 0b10001, 0b01110},
+	'9': {0b01110, 0b10001, 0b10001, 0b01111, 0b00001, 0b00010, 0b01100},
+	'A': {0b01110, 0b10001, 0b10001, 0b11111, 0b10001, 0b10001, 0b10001},
+	'B': {0b11110, 0b10001, 0b10001, 0b11110, 0b10001, 0b10001, 0b11110},
+	'C': {0b01110, 0b10001, 0b10000, 0b10000, 0b10000, 0b10001, 0b01110},
+	'D': {0b11100, 0b10010, 0b10001, 0b10001, 0b10001, 0b10010, 0b11100},
+	'E': {0b11111, 0b10000, 0b10000, 0b11110, 0b10000, 0b10000, 0b11111},
+	'F': {0b11111, 0b10000, 0b10000, 0b11110, 0b10000, 0b10000, 0b10000},
+	'G': {0b01110, 0b10001, 0b10000, 0b10111, 0b10001, 0b10001, 0b01111},
+	'H': {0b10001, 0b10001, 0b10001, 0b11111, 0b10001, 0b10001, 0b10001},
+	'I': {0b01110, 0b00100, 0b00100, 0b00100, 0b00100, 0b00100, 0b01110},
+	'J': {0b00111, 0b00010, 0b00010, 0b00010, 0b00010, 0b10010, 0b01100},
+	'K': {0b10001, 0b10010, 0b10100, 0b11000, 0b10100, 0b10010, 0b10001},
+	'L': {0b10000, 0b10000, 0b10000, 0b10000, 0b10000, 0b10000, 0b11111},
+	'M': {0b10001, 0b11011, 0b10101, 0b10101, 0b10001, 0b10001, 0b10001},
+	'N': {0b10001, 0b10001, 0b11001, 0b10101, 0b10011, 0b10001, 0b10001},
+	'O': {0b01110, 0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b01110},
+	'P': {0b11110, 0b10001, 0b10001, 0b11110, 0b10000, 0b10000, 0b10000},
+	'Q': {0b01110, 0b10001, 0b10001, 0b10001, 0b10101, 0b10010, 0b01101},
+	'R': {0b11110, 0b10001, 0b10001, 0b11110, 0b10100, 0b10010, 0b10001},
+	'S': {0b01111, 0b10000, 0b10000, 0b01110, 0b00001, 0b00001, 0b11110},
+	'T': {0b11111, 0b00100, 0b00100, 0b00100, 0b00100, 0b00100, 0b00100},
+	'U': {0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b01110},
+	'V': {0b10001, 0b10001, 0b10001, 0b10001, 0b10001, 0b01010, 0b00100},
+	'W': {0b10001, 0b10001, 0b10001, 0b10101, 0b10101, 0b10101, 0b01010},
+	'X': {0b10001, 0b10001, 0b01010, 0b00100, 0b01010, 0b10001, 0b10001},
+	'Y': {0b10001, 0b10001, 0b10001, 0b01010, 0b00100, 0b00100, 0b00100},
+	'Z': {0b11111, 0b00001, 0b00010, 0b00100, 0b01000, 0b10000, 0b11111},
+	'?': {0b01110, 0b10001, 0b00001, 0b00010, 0b00100, 0b00000, 0b00100},
+}
+
+func glyph(r rune) [glyphHeight]uint8 {
+	return glyphs[glyphRune(r)]
+}
+
+// glyphRune returns character of glyphs which is drawn for r.
+func glyphRune(r rune) rune {
+	if _, ok := glyphs[unicode.ToUpper(r)]; ok {
+		return unicode.ToUpper(r)
+	}
+	return '?'
+}
+
+// checkGlyphs returns error if two numbers of the board would be drawn the same way,
+// because their symbols differ only in case or they have no glyphs.
+func checkGlyphs(l *layout) error {
+	drawn := make(map[string]string, l.board.Size())
+	for n := 1; n <= l.board.Size(); n++ {
+		symbol := l.symbol(uint16(n))
+		key := strings.Map(glyphRune, symbol)
+		if other, ok := drawn[key]; ok {
+			return fmt.Errorf("symbols %q and %q cannot be distinguished in raster image", other, symbol)
+		}
+		drawn[key] = symbol
+	}
+	return nil
+}
+
+// drawText draws text centered at cx, cy with bitmap font, scaled by the largest integer
+// factor with which it fits in maxSize x maxSize square (but at least 1). Bold text has
+// glyph pixels widened by half of the scale.
+func drawText(img *image.RGBA, text string, cx, cy, maxSize int, c color.RGBA, bold bool) {
+	runes := []rune(text)
+	width := len(runes)*(glyphWidth+1) - 1
+	scale := maxSize / glyphHeight
+	if s := maxSize / width; s < scale {
+		scale = s
+	}
+	if scale < 1 {
+		scale = 1
+	}
+	boldWidth := 0
+	if bold {
+		boldWidth = (scale + 1) / 2
+	}
+	left := cx - (width*scale+boldWidth)/2
+	top := cy - glyphHeight*scale/2
+	for i, r := range runes {
+		g := glyph(r)
+		x0 := left + i*(glyphWidth+1)*scale
+		for row, bits := range g {
+			for column := 0; column < glyphWidth; column++ {
+				if bits&(1<<(glyphWidth-1-column)) == 0 {
+					continue
+				}
+				pixel := image.Rect(0, 0, scale+boldWidth, scale).Add(image.Pt(x0+column*scale, top+row*scale))
+				fillRect(img, pixel, c)
+			}
+		}
+	}
+}
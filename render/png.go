@@ -0,0 +1,77 @@
+package render
+
+import (
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"io"
+
+	"github.com/tomaszmj/sudoku/board"
+)
+
+// PNG writes board as PNG image (see package description and Options).
+func PNG(writer io.Writer, b *board.Board, options Options) error {
+	img, err := Image(b, options)
+	if err != nil {
+		return err
+	}
+	return png.Encode(writer, img)
+}
+
+// Image draws board as raster image, numbers are written with bitmap font (see PNG).
+// The font has only digits and uppercase letters (lowercase ones are drawn as uppercase),
+// so it returns error if symbols of two numbers of the board alphabet would look the same.
+func Image(b *board.Board, options Options) (*image.RGBA, error) {
+	l, err := newLayout(b, options)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkGlyphs(l); err != nil {
+		return nil, err
+	}
+	img := image.NewRGBA(image.Rect(0, 0, l.imageSize, l.imageSize))
+	fillRect(img, img.Bounds(), backgroundColor)
+	for _, seg := range l.segments() {
+		lineColor, width := thinLineColor, l.thinWidth
+		if seg.thick {
+			lineColor, width = thickLineColor, l.thickWidth
+		}
+		// the line is a rectangle centered on the border, thick lines are extended
+		// by half of the width on both ends, so that corners are filled
+		x1, y1, x2, y2 := l.position(seg.x1), l.position(seg.y1), l.position(seg.x2), l.position(seg.y2)
+		extension := 0
+		if seg.thick {
+			extension = width / 2
+		}
+		var r image.Rectangle
+		if y1 == y2 {
+			r = image.Rect(x1-extension, y1-width/2, x2+width-width/2+extension, y1+width-width/2)
+		} else {
+			r = image.Rect(x1-width/2, y1-extension, x1+width-width/2, y2+width-width/2+extension)
+		}
+		fillRect(img, r, lineColor)
+	}
+	pencilSize := l.cellSize / l.pencilColumns
+	b.ForEach(func(x, y int, n uint16) {
+		if n == 0 {
+			for _, mark := range l.pencilMarks(x, y) {
+				cx, cy := l.pencilCenter(x, y, mark)
+				drawText(img, l.symbol(mark), int(cx), int(cy), pencilSize-2, candidateColor, false)
+			}
+			return
+		}
+		cx := l.position(x) + l.cellSize/2
+		cy := l.position(y) + l.cellSize/2
+		if l.isGiven(x, y) {
+			drawText(img, l.symbol(n), cx, cy, l.cellSize*6/10, givenColor, true)
+		} else {
+			drawText(img, l.symbol(n), cx, cy, l.cellSize*6/10, filledColor, false)
+		}
+	})
+	return img, nil
+}
+
+func fillRect(img *image.RGBA, r image.Rectangle, c color.RGBA) {
+	draw.Draw(img, r, &image.Uniform{C: c}, image.Point{}, draw.Src)
+}
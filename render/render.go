@@ -0,0 +1,162 @@
+// Package render draws boards as images - SVG (see SVG) and PNG (see PNG), e.g. to print
+// puzzles or share them in chat. Thin lines separate fields and thick lines mark subgrid
+// (or jigsaw region) borders. Givens (see board.Board.SetGiven) are drawn in black bold,
+// other numbers (e.g. filled by solver) in blue. If no field is marked as given,
+// all numbers are drawn as givens. Numbers are written with symbols of the board
+// alphabet if it is set (see board.Board.SetAlphabet). Empty fields can show pencil marks
+// (see Options). Other sudoku variants (diagonal, cages, constraints) are not drawn.
+package render
+
+import (
+	"fmt"
+	"image/color"
+	"strconv"
+
+	"github.com/tomaszmj/sudoku/board"
+)
+
+// DefaultCellSize is size of a field in pixels used if Options.CellSize is 0.
+const DefaultCellSize = 48
+
+// minCellSize is the smallest size of a field, in which numbers can be drawn with bitmap font.
+const minCellSize = 2 * glyphHeight
+
+// Options describe how the board is drawn. Zero value is valid - board is drawn
+// with default cell size and without pencil marks.
+type Options struct {
+	// CellSize is size of a field in pixels. If it is 0, DefaultCellSize is used.
+	CellSize int
+	// Candidates are pencil marks drawn on empty fields (see board.NewCandidates).
+	// If it is nil, pencil marks are not drawn.
+	Candidates *board.Candidates
+}
+
+var (
+	backgroundColor = color.RGBA{0xff, 0xff, 0xff, 0xff}
+	thickLineColor  = color.RGBA{0x00, 0x00, 0x00, 0xff}
+	thinLineColor   = color.RGBA{0x99, 0x99, 0x99, 0xff}
+	givenColor      = color.RGBA{0x00, 0x00, 0x00, 0xff}
+	filledColor     = color.RGBA{0x1f, 0x5f, 0xbf, 0xff}
+	candidateColor  = color.RGBA{0x66, 0x66, 0x66, 0xff}
+)
+
+// layout holds dimensions (in pixels) of the drawn board.
+type layout struct {
+	board      *board.Board
+	candidates *board.Candidates
+	givens     bool // false if no field is marked as given, so all numbers are drawn as givens
+	cellSize   int
+	thinWidth  int
+	thickWidth int
+	margin     int // space around the board, so that thick outer border is not cut
+	imageSize  int
+	// pencilColumns is number of columns (and rows) of pencil marks in a field
+	pencilColumns int
+}
+
+func newLayout(b *board.Board, options Options) (*layout, error) {
+	cellSize := options.CellSize
+	if cellSize == 0 {
+		cellSize = DefaultCellSize
+	}
+	if cellSize < minCellSize {
+		return nil, fmt.Errorf("cell size must be at least %d, got %d", minCellSize, cellSize)
+	}
+	if options.Candidates != nil && options.Candidates.Size() != b.Size() {
+		return nil, fmt.Errorf("candidates size %d does not match board size %d", options.Candidates.Size(), b.Size())
+	}
+	thickWidth := cellSize / 16
+	if thickWidth < 2 {
+		thickWidth = 2
+	}
+	pencilColumns := 1
+	for pencilColumns*pencilColumns < b.Size() {
+		pencilColumns++
+	}
+	return &layout{
+		board:         b,
+		candidates:    options.Candidates,
+		givens:        b.HasGivens(),
+		cellSize:      cellSize,
+		thinWidth:     1,
+		thickWidth:    thickWidth,
+		margin:        thickWidth,
+		imageSize:     b.Size()*cellSize + 2*thickWidth,
+		pencilColumns: pencilColumns,
+	}, nil
+}
+
+// position returns pixel coordinate of the border line with given index (0 is the left / top border).
+func (l *layout) position(line int) int {
+	return l.margin + line*l.cellSize
+}
+
+// segment is a border between two fields (or a field and outside of the board),
+// from point x1, y1 to x2, y2 in field coordinates (only one of them differs).
+type segment struct {
+	x1, y1, x2, y2 int
+	thick          bool
+}
+
+// segments returns borders of all fields - thin ones first, so that thick ones are drawn over them.
+func (l *layout) segments() []segment {
+	size := l.board.Size()
+	var thin, thick []segment
+	add := func(s segment) {
+		if s.thick {
+			thick = append(thick, s)
+		} else {
+			thin = append(thin, s)
+		}
+	}
+	for y := 0; y <= size; y++ {
+		for x := 0; x < size; x++ {
+			border := y == 0 || y == size || !l.board.HaveCommonSubgrid(x, y-1, x, y)
+			add(segment{x, y, x + 1, y, border})
+		}
+	}
+	for x := 0; x <= size; x++ {
+		for y := 0; y < size; y++ {
+			border := x == 0 || x == size || !l.board.HaveCommonSubgrid(x-1, y, x, y)
+			add(segment{x, y, x, y + 1, border})
+		}
+	}
+	return append(thin, thick...)
+}
+
+// symbol returns text of number n (which must not be 0).
+func (l *layout) symbol(n uint16) string {
+	if a := l.board.Alphabet(); a != nil {
+		return a.Symbol(n)
+	}
+	return strconv.Itoa(int(n))
+}
+
+// isGiven returns true if number on field x, y is drawn as given.
+func (l *layout) isGiven(x, y int) bool {
+	return !l.givens || l.board.IsGiven(x, y)
+}
+
+// pencilMarks returns candidates of empty field x, y which are drawn as pencil marks.
+func (l *layout) pencilMarks(x, y int) []uint16 {
+	if l.candidates == nil || l.board.Get(x, y) != 0 {
+		return nil
+	}
+	var marks []uint16
+	for n := 1; n <= l.board.Size(); n++ {
+		if l.candidates.Has(x, y, uint16(n)) {
+			marks = append(marks, uint16(n))
+		}
+	}
+	return marks
+}
+
+// pencilCenter returns pixel coordinates of the center of pencil mark n on field x, y -
+// pencil marks are placed in pencilColumns x pencilColumns grid within the field.
+func (l *layout) pencilCenter(x, y int, n uint16) (float64, float64) {
+	i := int(n) - 1
+	pencilSize := float64(l.cellSize) / float64(l.pencilColumns)
+	cx := float64(l.position(x)) + (float64(i%l.pencilColumns)+0.5)*pencilSize
+	cy := float64(l.position(y)) + (float64(i/l.pencilColumns)+0.5)*pencilSize
+	return cx, cy
+}
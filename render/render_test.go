@@ -0,0 +1,169 @@
+package render_test
+
+import (
+	"bytes"
+	"image"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tomaszmj/sudoku/board"
+	"github.com/tomaszmj/sudoku/internal/testutil"
+	"github.com/tomaszmj/sudoku/render"
+)
+
+// hasColor returns true if any pixel of the field x, y has color c.
+func hasColor(img image.Image, cellSize, margin, x, y int, c color.RGBA) bool {
+	for py := margin + y*cellSize + 2; py < margin+(y+1)*cellSize-2; py++ {
+		for px := margin + x*cellSize + 2; px < margin+(x+1)*cellSize-2; px++ {
+			r, g, b, a := img.At(px, py).RGBA()
+			if uint8(r>>8) == c.R && uint8(g>>8) == c.G && uint8(b>>8) == c.B && uint8(a>>8) == c.A {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func TestPNG(t *testing.T) {
+	b := testutil.ReadBoard(t, "../cmd/boards/6x6.txt")
+	b.MarkGivens()
+	empty := board.Field{X: -1, Y: -1}
+	filled := board.Field{X: -1, Y: -1}
+	b.ForEach(func(x, y int, n uint16) {
+		if n == 0 && empty.X < 0 {
+			empty = board.Field{X: x, Y: y}
+		}
+	})
+	require.True(t, empty.X >= 0)
+	b.ForEach(func(x, y int, n uint16) {
+		if n == 0 && filled.X < 0 && (x != empty.X || y != empty.Y) {
+			filled = board.Field{X: x, Y: y}
+		}
+	})
+	b.Set(filled.X, filled.Y, 1)
+
+	var buf bytes.Buffer
+	options := render.Options{CellSize: 40, Candidates: board.NewCandidates(b)}
+	require.NoError(t, render.PNG(&buf, b, options))
+	img, err := png.Decode(&buf)
+	require.NoError(t, err)
+	margin := 2 // thick line width for cell size 40
+	assert.Equal(t, 6*40+2*margin, img.Bounds().Dx())
+	assert.Equal(t, 6*40+2*margin, img.Bounds().Dy())
+
+	black := color.RGBA{0x00, 0x00, 0x00, 0xff}
+	blue := color.RGBA{0x1f, 0x5f, 0xbf, 0xff}
+	gray := color.RGBA{0x66, 0x66, 0x66, 0xff}
+	var given board.Field
+	b.ForEach(func(x, y int, n uint16) {
+		if b.IsGiven(x, y) {
+			given = board.Field{X: x, Y: y}
+		}
+	})
+	assert.True(t, hasColor(img, 40, margin, given.X, given.Y, black))
+	assert.False(t, hasColor(img, 40, margin, given.X, given.Y, blue))
+	assert.True(t, hasColor(img, 40, margin, filled.X, filled.Y, blue))
+	assert.False(t, hasColor(img, 40, margin, filled.X, filled.Y, black))
+	assert.True(t, hasColor(img, 40, margin, empty.X, empty.Y, gray))
+
+	// subgrid of 6x6 board is 3x2 - thick line between columns 2 and 3, thin between 1 and 2
+	assert.Equal(t, black, color.RGBAModel.Convert(img.At(margin+3*40, margin+20)))
+	assert.Equal(t, black, color.RGBAModel.Convert(img.At(margin+3*40-1, margin+20)))
+	assert.Equal(t, color.RGBA{0x99, 0x99, 0x99, 0xff}, color.RGBAModel.Convert(img.At(margin+2*40, margin+20)))
+	assert.Equal(t, color.RGBA{0xff, 0xff, 0xff, 0xff}, color.RGBAModel.Convert(img.At(margin+2*40-1, margin+20)))
+	// thick line between rows 1 and 2
+	assert.Equal(t, black, color.RGBAModel.Convert(img.At(margin+20, margin+2*40)))
+
+	// without pencil marks empty field is blank
+	imgWithoutMarks, err := render.Image(b, render.Options{CellSize: 40})
+	require.NoError(t, err)
+	assert.False(t, hasColor(imgWithoutMarks, 40, margin, empty.X, empty.Y, gray))
+}
+
+func TestSVG(t *testing.T) {
+	b := testutil.ReadBoard(t, "../cmd/boards/jigsaw9x9.txt")
+	givens := 0
+	b.ForEach(func(x, y int, n uint16) {
+		if n != 0 {
+			givens++
+		}
+	})
+	b.MarkGivens()
+	b.Set(0, 0, 1)
+
+	var s strings.Builder
+	require.NoError(t, render.SVG(&s, b, render.Options{}))
+	svg := s.String()
+	assert.True(t, strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="438" height="438"`))
+	assert.True(t, strings.HasSuffix(svg, "</svg>\n"))
+	assert.Equal(t, givens+1, strings.Count(svg, "<text"))
+	assert.Equal(t, givens, strings.Count(svg, `font-weight="bold"`))
+	assert.Equal(t, 1, strings.Count(svg, `fill="#1f5fbf"`))
+	assert.Equal(t, 2*9*10, strings.Count(svg, "<line"))
+	// outer border and region borders are thick, the other borders are thin
+	thick := strings.Count(svg, `stroke="#000000"`)
+	assert.True(t, thick > 4*9 && thick < 2*9*10, "thick lines: %d", thick)
+
+	// board without givens - all numbers are drawn as givens
+	b2 := testutil.ReadBoard(t, "../cmd/boards/jigsaw9x9.txt")
+	s.Reset()
+	require.NoError(t, render.SVG(&s, b2, render.Options{}))
+	assert.Equal(t, givens, strings.Count(s.String(), `font-weight="bold"`))
+
+	// pencil marks of empty fields
+	s.Reset()
+	candidates := board.NewCandidates(b2)
+	require.NoError(t, render.SVG(&s, b2, render.Options{Candidates: candidates}))
+	marks := 0
+	b2.ForEach(func(x, y int, n uint16) {
+		marks += candidates.Get(x, y).Len()
+	})
+	assert.Equal(t, givens+marks, strings.Count(s.String(), "<text"))
+}
+
+func TestAlphabet(t *testing.T) {
+	b := testutil.ReadBoard(t, "../cmd/boards/16x16_symbols.txt")
+	var s strings.Builder
+	require.NoError(t, render.SVG(&s, b, render.Options{}))
+	assert.Contains(t, s.String(), ">G</text>")
+	assert.NotContains(t, s.String(), ">16</text>")
+	_, err := render.Image(b, render.Options{})
+	assert.NoError(t, err)
+
+	// lowercase letters are drawn as uppercase in raster image, SVG uses the symbols as they are
+	mixedCase, err := board.NewAlphabet("aA")
+	require.NoError(t, err)
+	b2, err := board.New(2, 1)
+	require.NoError(t, err)
+	require.NoError(t, b2.SetAlphabet(mixedCase))
+	_, err = render.Image(b2, render.Options{})
+	assert.EqualError(t, err, `symbols "a" and "A" cannot be distinguished in raster image`)
+	assert.Error(t, render.PNG(&bytes.Buffer{}, b2, render.Options{}))
+	assert.NoError(t, render.SVG(&strings.Builder{}, b2, render.Options{}))
+
+	// only symbols used by the board are checked
+	b3, err := board.New(1, 1)
+	require.NoError(t, err)
+	require.NoError(t, b3.SetAlphabet(mixedCase))
+	_, err = render.Image(b3, render.Options{})
+	assert.NoError(t, err)
+}
+
+func TestInvalidOptions(t *testing.T) {
+	b, err := board.New(3, 3)
+	require.NoError(t, err)
+	other, err := board.New(2, 2)
+	require.NoError(t, err)
+	for _, options := range []render.Options{
+		{CellSize: 5},
+		{CellSize: -1},
+		{Candidates: board.NewCandidates(other)},
+	} {
+		assert.Error(t, render.SVG(&strings.Builder{}, b, options))
+		assert.Error(t, render.PNG(&bytes.Buffer{}, b, options))
+	}
+}
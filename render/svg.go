@@ -0,0 +1,66 @@
+package render
+
+import (
+	"fmt"
+	"html"
+	"image/color"
+	"io"
+	"strings"
+
+	"github.com/tomaszmj/sudoku/board"
+)
+
+// SVG writes board as SVG image (see package description and Options).
+func SVG(writer io.Writer, b *board.Board, options Options) error {
+	l, err := newLayout(b, options)
+	if err != nil {
+		return err
+	}
+	var s strings.Builder
+	fmt.Fprintf(&s, `<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 %d %d">`+"\n",
+		l.imageSize, l.imageSize, l.imageSize, l.imageSize)
+	fmt.Fprintf(&s, `<rect width="100%%" height="100%%" fill="%s"/>`+"\n", svgColor(backgroundColor))
+	for _, seg := range l.segments() {
+		lineColor, width, linecap := thinLineColor, l.thinWidth, "butt"
+		if seg.thick {
+			lineColor, width, linecap = thickLineColor, l.thickWidth, "square"
+		}
+		fmt.Fprintf(&s, `<line x1="%d" y1="%d" x2="%d" y2="%d" stroke="%s" stroke-width="%d" stroke-linecap="%s"/>`+"\n",
+			l.position(seg.x1), l.position(seg.y1), l.position(seg.x2), l.position(seg.y2), svgColor(lineColor), width, linecap)
+	}
+	fontSize := float64(l.cellSize) * 0.6
+	pencilFontSize := float64(l.cellSize) / float64(l.pencilColumns) * 0.7
+	b.ForEach(func(x, y int, n uint16) {
+		if n == 0 {
+			for _, mark := range l.pencilMarks(x, y) {
+				cx, cy := l.pencilCenter(x, y, mark)
+				writeSVGText(&s, cx, cy, pencilFontSize, candidateColor, false, l.symbol(mark))
+			}
+			return
+		}
+		cx := float64(l.position(x)) + float64(l.cellSize)/2
+		cy := float64(l.position(y)) + float64(l.cellSize)/2
+		if l.isGiven(x, y) {
+			writeSVGText(&s, cx, cy, fontSize, givenColor, true, l.symbol(n))
+		} else {
+			writeSVGText(&s, cx, cy, fontSize, filledColor, false, l.symbol(n))
+		}
+	})
+	s.WriteString("</svg>\n")
+	_, err = io.WriteString(writer, s.String())
+	return err
+}
+
+// writeSVGText writes text centered at cx, cy.
+func writeSVGText(s *strings.Builder, cx, cy, fontSize float64, c color.RGBA, bold bool, text string) {
+	weight := "normal"
+	if bold {
+		weight = "bold"
+	}
+	fmt.Fprintf(s, `<text x="%.1f" y="%.1f" font-family="sans-serif" font-size="%.1f" font-weight="%s" fill="%s" `+
+		`text-anchor="middle" dominant-baseline="central">%s</text>`+"\n", cx, cy, fontSize, weight, svgColor(c), html.EscapeString(text))
+}
+
+func svgColor(c color.RGBA) string {
+	return fmt.Sprintf("#%02x%02x%02x", c.R, c.G, c.B)
+}
@@ -0,0 +1,228 @@
+package sat
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Literal is a variable (positive) or its negation (negative), as in DIMACS format.
+// Variables are numbered from 1, 0 is not a valid literal.
+type Literal int
+
+// Variable returns variable of the literal.
+func (l Literal) Variable() int {
+	if l < 0 {
+		return int(-l)
+	}
+	return int(l)
+}
+
+// Clause is a disjunction of literals.
+type Clause []Literal
+
+// CNF is a boolean formula in conjunctive normal form - conjunction of Clauses
+// with variables from 1 to VariablesCount.
+type CNF struct {
+	VariablesCount int
+	Clauses        []Clause
+}
+
+// NewVariable adds new variable to the formula and returns it.
+func (c *CNF) NewVariable() int {
+	c.VariablesCount++
+	return c.VariablesCount
+}
+
+// Add adds clause with given literals.
+func (c *CNF) Add(literals ...Literal) {
+	c.Clauses = append(c.Clauses, Clause(literals))
+}
+
+// pairwiseLimit is maximum number of literals for which AtMostOne uses pairwise encoding
+// (number of clauses grows quadratically with it, so for more literals auxiliary variables are used).
+const pairwiseLimit = 6
+
+// AtMostOne adds clauses satisfied only if at most one of given literals is true.
+// For a few literals each pair of them is excluded, otherwise sequential counter encoding
+// (with len(literals)-1 auxiliary variables and about 3*len(literals) clauses) is used.
+func (c *CNF) AtMostOne(literals []Literal) {
+	if len(literals) <= pairwiseLimit {
+		for i := range literals {
+			for j := i + 1; j < len(literals); j++ {
+				c.Add(-literals[i], -literals[j])
+			}
+		}
+		return
+	}
+	// s is true if any of literals up to i is true
+	s := Literal(c.NewVariable())
+	c.Add(-literals[0], s)
+	for i := 1; i < len(literals)-1; i++ {
+		next := Literal(c.NewVariable())
+		c.Add(-literals[i], next)
+		c.Add(-s, next)
+		c.Add(-literals[i], -s)
+		s = next
+	}
+	c.Add(-literals[len(literals)-1], -s)
+}
+
+// ExactlyOne adds clauses satisfied only if exactly one of given literals is true.
+func (c *CNF) ExactlyOne(literals []Literal) {
+	c.Add(append([]Literal(nil), literals...)...)
+	c.AtMostOne(literals)
+}
+
+// WriteDIMACS writes the formula in DIMACS CNF format, for example:
+// p cnf 3 2
+// 1 -2 0
+// 2 3 0
+func (c *CNF) WriteDIMACS(writer io.Writer) error {
+	w := bufio.NewWriter(writer)
+	fmt.Fprintf(w, "p cnf %d %d\n", c.VariablesCount, len(c.Clauses))
+	for _, clause := range c.Clauses {
+		for _, l := range clause {
+			w.WriteString(strconv.Itoa(int(l)))
+			w.WriteByte(' ')
+		}
+		w.WriteString("0\n")
+	}
+	return w.Flush()
+}
+
+// ReadDIMACS reads formula in DIMACS CNF format (see WriteDIMACS). Comment lines
+// (starting with "c") are ignored, clauses can span many lines.
+func ReadDIMACS(reader io.Reader) (*CNF, error) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(nil, 1024*1024)
+	var cnf *CNF
+	clausesCount := 0
+	var clause Clause
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || fields[0] == "c" || fields[0] == "%" {
+			continue
+		}
+		if fields[0] == "p" {
+			if cnf != nil || len(fields) != 4 || fields[1] != "cnf" {
+				return nil, fmt.Errorf("invalid problem line %d: %s", lineNumber, scanner.Text())
+			}
+			variablesCount, err1 := strconv.Atoi(fields[2])
+			count, err2 := strconv.Atoi(fields[3])
+			if err1 != nil || err2 != nil || variablesCount < 0 || count < 0 {
+				return nil, fmt.Errorf("invalid problem line %d: %s", lineNumber, scanner.Text())
+			}
+			cnf = &CNF{VariablesCount: variablesCount}
+			clausesCount = count
+			continue
+		}
+		if cnf == nil {
+			return nil, fmt.Errorf("clause before problem line in line %d: %s", lineNumber, scanner.Text())
+		}
+		for _, field := range fields {
+			l, err := strconv.Atoi(field)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing literal %w in line %d: %s", err, lineNumber, scanner.Text())
+			}
+			if l == 0 {
+				cnf.Clauses = append(cnf.Clauses, clause)
+				clause = nil
+				continue
+			}
+			if Literal(l).Variable() > cnf.VariablesCount {
+				return nil, fmt.Errorf("invalid variable %d in line %d, expected at most %d", l, lineNumber, cnf.VariablesCount)
+			}
+			clause = append(clause, Literal(l))
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if cnf == nil {
+		return nil, fmt.Errorf("error - no problem line")
+	}
+	if clause != nil {
+		return nil, fmt.Errorf("last clause is not terminated with 0")
+	}
+	if len(cnf.Clauses) != clausesCount {
+		return nil, fmt.Errorf("invalid number of clauses, expected %d, got %d", clausesCount, len(cnf.Clauses))
+	}
+	return cnf, nil
+}
+
+// Model is assignment of variables satisfying a formula - Model[v] is value
+// of variable v (Model[0] is not used).
+type Model []bool
+
+// Value returns value of literal l in the model.
+func (m Model) Value(l Literal) bool {
+	if l < 0 {
+		return !m[-l]
+	}
+	return m[l]
+}
+
+// ErrUnsatisfiable is returned by ReadModel if SAT solver has reported that formula is unsatisfiable.
+var ErrUnsatisfiable = errors.New("formula is unsatisfiable")
+
+// ReadModel reads model in the format used by SAT solvers in competitions,
+// i.e. "s SATISFIABLE" line followed by "v" lines with literals terminated with 0:
+// s SATISFIABLE
+// v 1 -2 3 0
+// Output of solvers (such as MiniSat) which write "SAT" followed by bare list of literals
+// is accepted as well. Variables missing in the output are false.
+func ReadModel(reader io.Reader, variablesCount int) (Model, error) {
+	scanner := bufio.NewScanner(reader)
+	scanner.Buffer(nil, 1024*1024)
+	model := make(Model, variablesCount+1)
+	satisfiable := false
+	for lineNumber := 1; scanner.Scan(); lineNumber++ {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 0 || fields[0] == "c" {
+			continue
+		}
+		switch fields[0] {
+		case "s":
+			fields = fields[1:]
+			if len(fields) == 1 && fields[0] == "SATISFIABLE" {
+				satisfiable = true
+				continue
+			}
+			if len(fields) == 1 && fields[0] == "UNSATISFIABLE" {
+				return nil, ErrUnsatisfiable
+			}
+			return nil, fmt.Errorf("unknown solution status in line %d: %s", lineNumber, scanner.Text())
+		case "SAT":
+			satisfiable = true
+			continue
+		case "UNSAT":
+			return nil, ErrUnsatisfiable
+		case "v":
+			fields = fields[1:]
+		}
+		for _, field := range fields {
+			l, err := strconv.Atoi(field)
+			if err != nil {
+				return nil, fmt.Errorf("error parsing literal %w in line %d: %s", err, lineNumber, scanner.Text())
+			}
+			if l == 0 {
+				continue
+			}
+			if Literal(l).Variable() > variablesCount {
+				return nil, fmt.Errorf("invalid variable %d in line %d, expected at most %d", l, lineNumber, variablesCount)
+			}
+			model[Literal(l).Variable()] = l > 0
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if !satisfiable {
+		return nil, fmt.Errorf("error - no solution status")
+	}
+	return model, nil
+}
@@ -0,0 +1,98 @@
+package sat_test
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tomaszmj/sudoku/sat"
+)
+
+func TestDIMACS(t *testing.T) {
+	cnf := &sat.CNF{VariablesCount: 3}
+	cnf.Add(1, -2)
+	cnf.Add(2, 3)
+	cnf.Add(-3)
+	var s strings.Builder
+	require.NoError(t, cnf.WriteDIMACS(&s))
+	assert.Equal(t, "p cnf 3 3\n1 -2 0\n2 3 0\n-3 0\n", s.String())
+
+	read, err := sat.ReadDIMACS(strings.NewReader("c comment\n" + s.String()))
+	require.NoError(t, err)
+	assert.Equal(t, cnf, read)
+
+	read, err = sat.ReadDIMACS(strings.NewReader("p cnf 2 2\n1\n-2 0 2\n0\n"))
+	require.NoError(t, err)
+	assert.Equal(t, []sat.Clause{{1, -2}, {2}}, read.Clauses)
+
+	for _, invalid := range []string{
+		"",
+		"1 2 0\n",
+		"p cnf 2\n",
+		"p cnf 2 1\n1 3 0\n",
+		"p cnf 2 1\n1 x 0\n",
+		"p cnf 2 2\n1 2 0\n",
+		"p cnf 2 1\n1 2\n",
+	} {
+		_, err := sat.ReadDIMACS(strings.NewReader(invalid))
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestReadModel(t *testing.T) {
+	model, err := sat.ReadModel(strings.NewReader("c comment\ns SATISFIABLE\nv 1 -2\nv 3 0\n"), 4)
+	require.NoError(t, err)
+	assert.Equal(t, sat.Model{false, true, false, true, false}, model)
+	assert.True(t, model.Value(-2))
+	assert.False(t, model.Value(-3))
+
+	model, err = sat.ReadModel(strings.NewReader("SAT\n-1 2 0\n"), 2)
+	require.NoError(t, err)
+	assert.Equal(t, sat.Model{false, false, true}, model)
+
+	_, err = sat.ReadModel(strings.NewReader("s UNSATISFIABLE\n"), 2)
+	assert.ErrorIs(t, err, sat.ErrUnsatisfiable)
+	_, err = sat.ReadModel(strings.NewReader("UNSAT\n"), 2)
+	assert.ErrorIs(t, err, sat.ErrUnsatisfiable)
+	for _, invalid := range []string{"", "v 1 2 0\n", "s SATISFIABLE\nv 1 3 0\n", "s UNKNOWN\n"} {
+		_, err := sat.ReadModel(strings.NewReader(invalid), 2)
+		assert.Error(t, err, invalid)
+	}
+}
+
+func TestAtMostOne(t *testing.T) {
+	for _, count := range []int{2, 6, 7, 20} {
+		cnf := &sat.CNF{VariablesCount: count}
+		literals := make([]sat.Literal, count)
+		for i := range literals {
+			literals[i] = sat.Literal(i + 1)
+		}
+		cnf.ExactlyOne(literals)
+		// exactly one of literals can be true, so there are count models (ignoring auxiliary variables)
+		s := sat.NewSolver(cnf)
+		models := 0
+		for {
+			found, err := s.Solve(context.Background())
+			require.NoError(t, err)
+			if !found {
+				break
+			}
+			models++
+			trueLiterals := 0
+			exclude := sat.Clause{}
+			for _, l := range literals {
+				if s.Model().Value(l) {
+					trueLiterals++
+					exclude = append(exclude, -l)
+				} else {
+					exclude = append(exclude, l)
+				}
+			}
+			assert.Equal(t, 1, trueLiterals)
+			require.NoError(t, s.AddClause(exclude))
+		}
+		assert.Equal(t, count, models)
+	}
+}
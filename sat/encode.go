@@ -0,0 +1,199 @@
+package sat
+
+import (
+	"fmt"
+
+	"github.com/tomaszmj/sudoku/board"
+)
+
+// Encoding is a board transformed into CNF formula. Variable of number n
+// on field x, y is (y*size+x)*size+n (see Variable), so variables from 1 to size^3
+// describe the board, next variables are auxiliary ones. Clauses ensure that:
+// - each field has exactly one number, numbers given on the board are kept,
+// - each unit (row, column, subgrid, diagonal) has each number exactly once,
+// - each Killer Sudoku cage has each number at most once and numbers in it add up to the cage sum,
+// - constraints (see board.Constraint) are satisfied - see encodeConstraint.
+type Encoding struct {
+	CNF   *CNF
+	board *board.Board
+	size  int
+}
+
+// MaxSize is the maximum size of the board that can be encoded. The encoding has size^3
+// variables and even more clauses, so it would not fit in memory for much larger grids
+// (and it would be too large to be solved anyway).
+const MaxSize = 64
+
+// Encode transforms board into CNF formula. Each model of the formula is a solution
+// of the board (see Decode), unless the board has constraints which cannot be encoded exactly
+// (see encodeConstraint) - such solution has to be checked with board.CheckRules.
+// It returns error if the board is larger than MaxSize.
+func Encode(b *board.Board) (*Encoding, error) {
+	size := b.Size()
+	if size > MaxSize {
+		return nil, fmt.Errorf("board size %d is too large to encode, max size is %d", size, MaxSize)
+	}
+	e := &Encoding{
+		CNF:   &CNF{VariablesCount: size * size * size},
+		board: b.Copy(),
+		size:  size,
+	}
+	literals := make([]Literal, size)
+	b.ForEach(func(x, y int, n uint16) {
+		for i := range literals {
+			literals[i] = e.Variable(x, y, uint16(i+1))
+		}
+		e.CNF.ExactlyOne(literals)
+		if n != 0 {
+			e.CNF.Add(e.Variable(x, y, n))
+		}
+	})
+	for _, unit := range b.Units() {
+		for n := 1; n <= size; n++ {
+			literals = literals[:0]
+			for _, f := range unit.Fields {
+				literals = append(literals, e.Variable(f.X, f.Y, uint16(n)))
+			}
+			e.CNF.ExactlyOne(literals)
+		}
+	}
+	for _, cage := range b.Cages() {
+		for n := 1; n <= size; n++ {
+			literals = literals[:0]
+			for _, f := range cage.Fields {
+				literals = append(literals, e.Variable(f.X, f.Y, uint16(n)))
+			}
+			e.CNF.AtMostOne(literals)
+		}
+		e.encodeCageSum(cage)
+	}
+	if len(b.Constraints()) > 0 {
+		empty := b.Copy()
+		empty.ForEach(func(x, y int, _ uint16) {
+			empty.Set(x, y, 0)
+		})
+		for _, c := range b.Constraints() {
+			e.encodeConstraint(empty, c)
+		}
+	}
+	return e, nil
+}
+
+// Variable returns variable which is true if number n is on field x, y.
+func (e *Encoding) Variable(x, y int, n uint16) Literal {
+	return Literal((y*e.size+x)*e.size + int(n))
+}
+
+// encodeCageSum adds clauses satisfied only if numbers in the cage add up to its sum.
+// Auxiliary variable partialSums[i][t] is implied if numbers on the first i+1 fields add up to t
+// (for each number n on field i+1, partialSums[i][t] implies partialSums[i+1][t+n]).
+// Partial sums greater than cage sum and other final sums than the cage sum are forbidden.
+func (e *Encoding) encodeCageSum(cage board.Cage) {
+	partialSums := make([][]Literal, len(cage.Fields))
+	for i := range partialSums {
+		partialSums[i] = make([]Literal, cage.Sum+1)
+		for t := range partialSums[i] {
+			partialSums[i][t] = Literal(e.CNF.NewVariable())
+		}
+	}
+	for i, f := range cage.Fields {
+		for n := 1; n <= e.size; n++ {
+			number := e.Variable(f.X, f.Y, uint16(n))
+			if i == 0 {
+				if n > cage.Sum {
+					e.CNF.Add(-number)
+				} else {
+					e.CNF.Add(-number, partialSums[0][n])
+				}
+				continue
+			}
+			for t, sum := range partialSums[i-1] {
+				if t+n > cage.Sum {
+					e.CNF.Add(-sum, -number)
+				} else {
+					e.CNF.Add(-sum, -number, partialSums[i][t+n])
+				}
+			}
+		}
+	}
+	last := partialSums[len(partialSums)-1]
+	for t := 0; t < cage.Sum; t++ {
+		e.CNF.Add(-last[t])
+	}
+}
+
+// encodeConstraint forbids numbers on single fields and pairs of fields of the constraint,
+// which break it when there are no other numbers on the board (empty is board with no numbers,
+// it is restored after the call). It is exact encoding of constraints which depend only
+// on pairs of fields, such as board.Thermometer or board.KropkiDot.
+func (e *Encoding) encodeConstraint(empty *board.Board, c board.Constraint) {
+	fields := c.Fields()
+	for _, f := range fields {
+		for n := 1; n <= e.size; n++ {
+			empty.Set(f.X, f.Y, uint16(n))
+			if c.Check(empty) != nil {
+				e.CNF.Add(-e.Variable(f.X, f.Y, uint16(n)))
+			}
+		}
+		empty.Set(f.X, f.Y, 0)
+	}
+	for i, f1 := range fields {
+		for _, f2 := range fields[i+1:] {
+			if f1 == f2 {
+				continue
+			}
+			for n1 := 1; n1 <= e.size; n1++ {
+				empty.Set(f1.X, f1.Y, uint16(n1))
+				for n2 := 1; n2 <= e.size; n2++ {
+					empty.Set(f2.X, f2.Y, uint16(n2))
+					if c.Check(empty) != nil {
+						e.CNF.Add(-e.Variable(f1.X, f1.Y, uint16(n1)), -e.Variable(f2.X, f2.Y, uint16(n2)))
+					}
+				}
+				empty.Set(f2.X, f2.Y, 0)
+			}
+			empty.Set(f1.X, f1.Y, 0)
+		}
+	}
+}
+
+// Decode returns board with numbers set according to the model. It returns error
+// if the model does not set exactly one number for some field.
+func (e *Encoding) Decode(m Model) (*board.Board, error) {
+	if len(m) <= e.size*e.size*e.size {
+		return nil, fmt.Errorf("model has %d variables, expected at least %d", len(m)-1, e.size*e.size*e.size)
+	}
+	b := e.board.Copy()
+	for y := 0; y < e.size; y++ {
+		for x := 0; x < e.size; x++ {
+			var number uint16
+			for n := uint16(1); int(n) <= e.size; n++ {
+				if !m.Value(e.Variable(x, y, n)) {
+					continue
+				}
+				if number != 0 {
+					return nil, fmt.Errorf("model has both %d and %d on field %d, %d", number, n, x, y)
+				}
+				number = n
+			}
+			if number == 0 {
+				return nil, fmt.Errorf("model has no number on field %d, %d", x, y)
+			}
+			b.Set(x, y, number)
+		}
+	}
+	return b, nil
+}
+
+// Exclude returns clause which excludes numbers of the board on given fields, i.e. it is
+// satisfied only if at least one of the fields has different number than on the board.
+// It can be added to the formula to find another model than the one decoded into the board.
+func (e *Encoding) Exclude(b *board.Board, fields []board.Field) Clause {
+	clause := make(Clause, 0, len(fields))
+	for _, f := range fields {
+		if n := b.Get(f.X, f.Y); n != 0 {
+			clause = append(clause, -e.Variable(f.X, f.Y, n))
+		}
+	}
+	return clause
+}
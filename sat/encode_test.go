@@ -0,0 +1,155 @@
+package sat_test
+
+import (
+	"context"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tomaszmj/sudoku/board"
+	"github.com/tomaszmj/sudoku/internal/testutil"
+	"github.com/tomaszmj/sudoku/sat"
+)
+
+func mustEncode(t *testing.T, b *board.Board) *sat.Encoding {
+	e, err := sat.Encode(b)
+	require.NoError(t, err)
+	return e
+}
+
+// solveAll returns all solutions of the encoded board.
+func solveAll(t *testing.T, e *sat.Encoding, emptyFields []board.Field) []*board.Board {
+	s := sat.NewSolver(e.CNF)
+	var solutions []*board.Board
+	for {
+		found, err := s.Solve(context.Background())
+		require.NoError(t, err)
+		if !found {
+			return solutions
+		}
+		solution, err := e.Decode(s.Model())
+		require.NoError(t, err)
+		solutions = append(solutions, solution)
+		require.NoError(t, s.AddClause(e.Exclude(solution, emptyFields)))
+	}
+}
+
+func allFields(b *board.Board) []board.Field {
+	var fields []board.Field
+	b.ForEach(func(x, y int, _ uint16) {
+		fields = append(fields, board.Field{X: x, Y: y})
+	})
+	return fields
+}
+
+func TestEncodeAndDecode(t *testing.T) {
+	for _, name := range []string{"difficult9x9", "killer9x9", "jigsaw9x9", "diagonal9x9"} {
+		t.Run(name, func(t *testing.T) {
+			b := testutil.ReadBoard(t, "../cmd/boards/"+name+".txt")
+			expected := testutil.ReadBoard(t, "../cmd/boards/"+name+"_solution.txt")
+			e := mustEncode(t, b)
+			assert.Equal(t, sat.Literal(1), e.Variable(0, 0, 1))
+			assert.Equal(t, sat.Literal(729), e.Variable(8, 8, 9))
+			assert.GreaterOrEqual(t, e.CNF.VariablesCount, 729)
+			solutions := solveAll(t, e, allFields(b))
+			require.Len(t, solutions, 1)
+			assert.Equal(t, expected.String(), solutions[0].String())
+			assert.NoError(t, solutions[0].CheckRules())
+			assert.Equal(t, b.Cages(), solutions[0].Cages())
+		})
+	}
+}
+
+func TestEncodeDIMACS(t *testing.T) {
+	b := testutil.ReadBoard(t, "../cmd/boards/very_difficult_9x9.txt")
+	expected := testutil.ReadBoard(t, "../cmd/boards/very_difficult_9x9_solution.txt")
+	e := mustEncode(t, b)
+	var s strings.Builder
+	require.NoError(t, e.CNF.WriteDIMACS(&s))
+	cnf, err := sat.ReadDIMACS(strings.NewReader(s.String()))
+	require.NoError(t, err)
+	solver := sat.NewSolver(cnf)
+	found, err := solver.Solve(context.Background())
+	require.NoError(t, err)
+	require.True(t, found)
+
+	// model written by external solver
+	var output strings.Builder
+	output.WriteString("s SATISFIABLE\nv")
+	for v := 1; v <= cnf.VariablesCount; v++ {
+		if solver.Model()[v] {
+			output.WriteString(" " + strconv.Itoa(v))
+		}
+	}
+	output.WriteString(" 0\n")
+	model, err := sat.ReadModel(strings.NewReader(output.String()), cnf.VariablesCount)
+	require.NoError(t, err)
+	solution, err := e.Decode(model)
+	require.NoError(t, err)
+	assert.Equal(t, expected.String(), solution.String())
+}
+
+func TestEncodeConstraints(t *testing.T) {
+	b, err := board.New(2, 2)
+	require.NoError(t, err)
+	require.NoError(t, b.AddConstraint(board.Thermometer{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}, {X: 3, Y: 0}}))
+	// the first row must be 1 2 3 4, that is 1 of 4! permutations of all 288 grids
+	solutions := solveAll(t, mustEncode(t, b), allFields(b))
+	assert.Len(t, solutions, 12)
+	for _, solution := range solutions {
+		assert.NoError(t, solution.CheckRules())
+	}
+
+	b, err = board.New(2, 2)
+	require.NoError(t, err)
+	require.NoError(t, b.AddCage(3, []board.Field{{X: 0, Y: 0}, {X: 1, Y: 0}}))
+	// the first subgrid starts with 1 2 or 2 1
+	solutions = solveAll(t, mustEncode(t, b), allFields(b))
+	assert.Len(t, solutions, 288/6)
+	for _, solution := range solutions {
+		assert.NoError(t, solution.CheckRules())
+	}
+}
+
+func TestDecodeErrors(t *testing.T) {
+	b, err := board.New(1, 2)
+	require.NoError(t, err)
+	e := mustEncode(t, b)
+	model := make(sat.Model, e.CNF.VariablesCount+1)
+	_, err = e.Decode(model[:5])
+	assert.Error(t, err)
+	_, err = e.Decode(model)
+	assert.Error(t, err, "no number on field")
+	for x := 0; x < 2; x++ {
+		for y := 0; y < 2; y++ {
+			model[e.Variable(x, y, 1)] = true
+		}
+	}
+	decoded, err := e.Decode(model)
+	require.NoError(t, err)
+	assert.Error(t, decoded.CheckRules(), "model does not have to satisfy the formula")
+	model[e.Variable(0, 0, 2)] = true
+	_, err = e.Decode(model)
+	assert.Error(t, err, "2 numbers on field")
+}
+
+func TestEncodeLargeBoard(t *testing.T) {
+	// backtracking solvers cannot fill in such board in reasonable time
+	b, err := board.New(6, 6)
+	require.NoError(t, err)
+	e := mustEncode(t, b)
+	s := sat.NewSolver(e.CNF)
+	found, err := s.Solve(context.Background())
+	require.NoError(t, err)
+	require.True(t, found)
+	solution, err := e.Decode(s.Model())
+	require.NoError(t, err)
+	assert.NoError(t, solution.CheckRules())
+
+	b, err = board.New(8, 9)
+	require.NoError(t, err)
+	_, err = sat.Encode(b)
+	assert.EqualError(t, err, "board size 72 is too large to encode, max size is 64")
+}
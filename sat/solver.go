@@ -0,0 +1,430 @@
+package sat
+
+import (
+	"container/heap"
+	"context"
+	"fmt"
+)
+
+// Solver is a CDCL (conflict-driven clause learning) SAT solver - DPLL search
+// (deciding values of variables and propagating unit clauses) extended with:
+// - learning clauses from conflicts (first unique implication point) and non-chronological backjumping,
+// - two watched literals per clause, so that only clauses which can become unit are visited,
+// - VSIDS heuristic - deciding variables which took part in recent conflicts first,
+// - phase saving and restarts (following Luby sequence).
+// Solver is incremental - clauses can be added after Solve (e.g. to exclude the model
+// found, so that the next Solve looks for another one).
+//
+// Internally literal of variable v (numbered from 0) is 2*v for positive literal
+// and 2*v+1 for its negation, so that literal can be used as a slice index.
+type Solver struct {
+	variablesCount int
+	clauses        [][]int // original and learnt clauses, 2 first literals are watched
+	watches        [][]int // indexes of clauses watching each literal
+	assigns        []int8  // value of each variable: 1 (true), -1 (false) or 0 (not assigned)
+	level          []int   // decision level at which each variable was assigned
+	reason         []int   // index of clause which implied each variable, -1 for decisions
+	phase          []bool  // last value of each variable (phase saving)
+	trail          []int   // assigned literals in order of assignment
+	trailLimits    []int   // trail length at the beginning of each decision level
+	propagated     int     // number of trail literals for which propagate has been done
+	order          variableOrder
+	activityInc    float64
+	seen           []bool // helper slice for analyze
+	unsatisfiable  bool   // conflict without decisions has been found
+	model          Model
+	stats          Stats
+}
+
+// Stats holds statistics of all searches performed by Solver.
+type Stats struct {
+	Decisions    int
+	Conflicts    int
+	Propagations int
+	Restarts     int
+}
+
+// NewSolver creates solver with clauses of the formula.
+func NewSolver(cnf *CNF) *Solver {
+	s := &Solver{
+		variablesCount: cnf.VariablesCount,
+		watches:        make([][]int, 2*cnf.VariablesCount),
+		assigns:        make([]int8, cnf.VariablesCount),
+		level:          make([]int, cnf.VariablesCount),
+		reason:         make([]int, cnf.VariablesCount),
+		phase:          make([]bool, cnf.VariablesCount),
+		seen:           make([]bool, cnf.VariablesCount),
+		activityInc:    1,
+		order: variableOrder{
+			activity: make([]float64, cnf.VariablesCount),
+			index:    make([]int, cnf.VariablesCount),
+		},
+	}
+	for v := 0; v < cnf.VariablesCount; v++ {
+		s.reason[v] = -1
+		heap.Push(&s.order, v)
+	}
+	for _, clause := range cnf.Clauses {
+		if err := s.AddClause(clause); err != nil {
+			panic(fmt.Sprintf("invalid CNF: %s", err))
+		}
+	}
+	return s
+}
+
+func literal(l Literal) int {
+	if l < 0 {
+		return 2*(int(-l)-1) + 1
+	}
+	return 2 * (int(l) - 1)
+}
+
+// value returns 1 if literal is true, -1 if it is false, 0 if it is not assigned.
+func (s *Solver) value(lit int) int8 {
+	v := s.assigns[lit>>1]
+	if lit&1 == 1 {
+		return -v
+	}
+	return v
+}
+
+func (s *Solver) decisionLevel() int {
+	return len(s.trailLimits)
+}
+
+// AddClause adds clause to the formula. It returns error if it contains invalid literal.
+func (s *Solver) AddClause(clause Clause) error {
+	s.backtrack(0)
+	lits := make([]int, 0, len(clause))
+	for _, l := range clause {
+		if l == 0 || l.Variable() > s.variablesCount {
+			return fmt.Errorf("invalid literal %d, variables are from 1 to %d", l, s.variablesCount)
+		}
+		lit := literal(l)
+		switch s.value(lit) {
+		case 1:
+			return nil // clause is already satisfied
+		case -1:
+			continue // false literal can be skipped
+		}
+		duplicate := false
+		for _, other := range lits {
+			if other == lit^1 {
+				return nil // tautology
+			}
+			duplicate = duplicate || other == lit
+		}
+		if !duplicate {
+			lits = append(lits, lit)
+		}
+	}
+	if s.unsatisfiable {
+		return nil
+	}
+	switch len(lits) {
+	case 0:
+		s.unsatisfiable = true
+	case 1:
+		s.assign(lits[0], -1)
+		if s.propagate() >= 0 {
+			s.unsatisfiable = true
+		}
+	default:
+		s.addClause(lits)
+	}
+	return nil
+}
+
+// addClause stores clause with at least 2 literals and watches its first 2 literals.
+func (s *Solver) addClause(lits []int) int {
+	index := len(s.clauses)
+	s.clauses = append(s.clauses, lits)
+	s.watches[lits[0]] = append(s.watches[lits[0]], index)
+	s.watches[lits[1]] = append(s.watches[lits[1]], index)
+	return index
+}
+
+func (s *Solver) assign(lit, reason int) {
+	v := lit >> 1
+	s.assigns[v] = 1
+	if lit&1 == 1 {
+		s.assigns[v] = -1
+	}
+	s.level[v] = s.decisionLevel()
+	s.reason[v] = reason
+	s.trail = append(s.trail, lit)
+}
+
+// propagate assigns literals implied by unit clauses. It returns index
+// of conflicting clause (with all literals false) or -1 if there is no conflict.
+func (s *Solver) propagate() int {
+	for s.propagated < len(s.trail) {
+		falseLit := s.trail[s.propagated] ^ 1
+		s.propagated++
+		s.stats.Propagations++
+		watches := s.watches[falseLit]
+		kept := 0
+		for i := 0; i < len(watches); i++ {
+			index := watches[i]
+			c := s.clauses[index]
+			// make sure that the false literal is the second one
+			if c[0] == falseLit {
+				c[0], c[1] = c[1], c[0]
+			}
+			if s.value(c[0]) == 1 {
+				watches[kept] = index
+				kept++
+				continue
+			}
+			// look for a new literal to watch
+			moved := false
+			for k := 2; k < len(c); k++ {
+				if s.value(c[k]) != -1 {
+					c[1], c[k] = c[k], c[1]
+					s.watches[c[1]] = append(s.watches[c[1]], index)
+					moved = true
+					break
+				}
+			}
+			if moved {
+				continue
+			}
+			watches[kept] = index
+			kept++
+			if s.value(c[0]) == -1 {
+				kept += copy(watches[kept:], watches[i+1:])
+				s.watches[falseLit] = watches[:kept]
+				s.propagated = len(s.trail)
+				return index
+			}
+			s.assign(c[0], index)
+		}
+		s.watches[falseLit] = watches[:kept]
+	}
+	return -1
+}
+
+// analyze returns clause learnt from conflict (its first literal is the only one
+// assigned at the current decision level) and decision level to backtrack to.
+func (s *Solver) analyze(conflict int) ([]int, int) {
+	learnt := []int{-1} // the first literal is set at the end
+	pathCount := 0
+	lit := -1
+	i := len(s.trail) - 1
+	for {
+		c := s.clauses[conflict]
+		start := 0
+		if lit != -1 {
+			start = 1 // the first literal of reason clause is the implied one
+		}
+		for _, q := range c[start:] {
+			v := q >> 1
+			if s.seen[v] || s.level[v] == 0 {
+				continue
+			}
+			s.seen[v] = true
+			s.bumpActivity(v)
+			if s.level[v] == s.decisionLevel() {
+				pathCount++
+			} else {
+				learnt = append(learnt, q)
+			}
+		}
+		for !s.seen[s.trail[i]>>1] {
+			i--
+		}
+		lit = s.trail[i]
+		i--
+		conflict = s.reason[lit>>1]
+		s.seen[lit>>1] = false
+		pathCount--
+		if pathCount == 0 {
+			break
+		}
+	}
+	learnt[0] = lit ^ 1
+	backtrackLevel := 0
+	for j := 1; j < len(learnt); j++ {
+		s.seen[learnt[j]>>1] = false
+		if l := s.level[learnt[j]>>1]; l > backtrackLevel {
+			backtrackLevel = l
+			learnt[1], learnt[j] = learnt[j], learnt[1]
+		}
+	}
+	return learnt, backtrackLevel
+}
+
+// backtrack reverts assignments made at decision levels greater than level.
+func (s *Solver) backtrack(level int) {
+	if s.decisionLevel() <= level {
+		return
+	}
+	for i := len(s.trail) - 1; i >= s.trailLimits[level]; i-- {
+		v := s.trail[i] >> 1
+		s.phase[v] = s.assigns[v] == 1
+		s.assigns[v] = 0
+		s.reason[v] = -1
+		if s.order.index[v] < 0 {
+			heap.Push(&s.order, v)
+		}
+	}
+	s.trail = s.trail[:s.trailLimits[level]]
+	s.trailLimits = s.trailLimits[:level]
+	s.propagated = len(s.trail)
+}
+
+const activityLimit = 1e100
+
+func (s *Solver) bumpActivity(v int) {
+	s.order.activity[v] += s.activityInc
+	if s.order.activity[v] > activityLimit {
+		for i := range s.order.activity {
+			s.order.activity[i] /= activityLimit
+		}
+		s.activityInc /= activityLimit
+	}
+	if s.order.index[v] >= 0 {
+		heap.Fix(&s.order, s.order.index[v])
+	}
+}
+
+// activityDecay makes activity of older conflicts less important (instead of decreasing
+// activity of all variables, activity increment for the next conflicts is increased).
+const activityDecay = 0.95
+
+// restartBase is number of conflicts after which search is restarted, multiplied by Luby sequence.
+const restartBase = 100
+
+// contextCheckInterval is number of search iterations after which Solve checks if context is done.
+const contextCheckInterval = 1024
+
+// Solve looks for a model of the formula. It returns true if the formula is satisfiable
+// (the model can be read with Model) or false if it is not. If ctx is done before
+// the search is finished, it returns ctx.Err() - then Solve can be called again to continue
+// the search (clauses learnt so far are kept).
+func (s *Solver) Solve(ctx context.Context) (bool, error) {
+	s.model = nil
+	if s.unsatisfiable {
+		return false, nil
+	}
+	s.backtrack(0)
+	restart := 1
+	conflictsLeft := restartBase * luby(restart)
+	for i := 0; ; i++ {
+		if i%contextCheckInterval == 0 {
+			select {
+			case <-ctx.Done():
+				s.backtrack(0)
+				return false, ctx.Err()
+			default:
+			}
+		}
+		conflict := s.propagate()
+		if conflict >= 0 {
+			s.stats.Conflicts++
+			if s.decisionLevel() == 0 {
+				s.unsatisfiable = true
+				return false, nil
+			}
+			learnt, level := s.analyze(conflict)
+			s.backtrack(level)
+			if len(learnt) == 1 {
+				s.assign(learnt[0], -1)
+			} else {
+				s.assign(learnt[0], s.addClause(learnt))
+			}
+			s.activityInc /= activityDecay
+			conflictsLeft--
+			continue
+		}
+		if conflictsLeft <= 0 {
+			s.stats.Restarts++
+			s.backtrack(0)
+			restart++
+			conflictsLeft = restartBase * luby(restart)
+			continue
+		}
+		v := s.nextDecision()
+		if v < 0 {
+			s.model = make(Model, s.variablesCount+1)
+			for v, value := range s.assigns {
+				s.model[v+1] = value == 1
+			}
+			return true, nil
+		}
+		s.stats.Decisions++
+		s.trailLimits = append(s.trailLimits, len(s.trail))
+		lit := 2*v + 1
+		if s.phase[v] {
+			lit = 2 * v
+		}
+		s.assign(lit, -1)
+	}
+}
+
+// nextDecision returns not assigned variable with the highest activity, or -1 if all are assigned.
+func (s *Solver) nextDecision() int {
+	for s.order.Len() > 0 {
+		v := heap.Pop(&s.order).(int)
+		if s.assigns[v] == 0 {
+			return v
+		}
+	}
+	return -1
+}
+
+// Model returns model found by the last Solve, or nil if it has not found any.
+func (s *Solver) Model() Model {
+	return s.model
+}
+
+func (s *Solver) Stats() Stats {
+	return s.stats
+}
+
+// luby returns i-th element (from 1) of Luby sequence: 1 1 2 1 1 2 4 1 1 2 1 1 2 4 8 ...
+func luby(i int) int {
+	for k := 1; ; k++ {
+		if i == 1<<uint(k)-1 {
+			return 1 << uint(k-1)
+		}
+		if i < 1<<uint(k)-1 {
+			return luby(i - 1<<uint(k-1) + 1)
+		}
+	}
+}
+
+// variableOrder is a heap of variables, the one with the highest activity is on top.
+type variableOrder struct {
+	variables []int
+	activity  []float64
+	index     []int // index of each variable in variables, -1 if it is not in the heap
+}
+
+func (o variableOrder) Len() int {
+	return len(o.variables)
+}
+
+func (o variableOrder) Less(i, j int) bool {
+	return o.activity[o.variables[i]] > o.activity[o.variables[j]]
+}
+
+func (o variableOrder) Swap(i, j int) {
+	o.variables[i], o.variables[j] = o.variables[j], o.variables[i]
+	o.index[o.variables[i]] = i
+	o.index[o.variables[j]] = j
+}
+
+func (o *variableOrder) Push(x interface{}) {
+	v := x.(int)
+	o.index[v] = len(o.variables)
+	o.variables = append(o.variables, v)
+}
+
+func (o *variableOrder) Pop() interface{} {
+	last := len(o.variables) - 1
+	v := o.variables[last]
+	o.variables = o.variables[:last]
+	o.index[v] = -1
+	return v
+}
@@ -0,0 +1,140 @@
+package sat_test
+
+import (
+	"context"
+	"math/rand"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tomaszmj/sudoku/sat"
+)
+
+// satisfiable checks all assignments of variables.
+func satisfiable(cnf *sat.CNF) bool {
+	for assignment := 0; assignment < 1<<uint(cnf.VariablesCount); assignment++ {
+		model := make(sat.Model, cnf.VariablesCount+1)
+		for v := 1; v <= cnf.VariablesCount; v++ {
+			model[v] = assignment&(1<<uint(v-1)) != 0
+		}
+		if satisfies(model, cnf) {
+			return true
+		}
+	}
+	return false
+}
+
+func satisfies(model sat.Model, cnf *sat.CNF) bool {
+	for _, clause := range cnf.Clauses {
+		satisfied := false
+		for _, l := range clause {
+			satisfied = satisfied || model.Value(l)
+		}
+		if !satisfied {
+			return false
+		}
+	}
+	return true
+}
+
+func TestSolverRandom3SAT(t *testing.T) {
+	random := rand.New(rand.NewSource(1))
+	for i := 0; i < 200; i++ {
+		// ratio of clauses to variables close to 4.26 gives both satisfiable and unsatisfiable formulas
+		cnf := &sat.CNF{VariablesCount: 12}
+		for c := 0; c < 51; c++ {
+			clause := make(sat.Clause, 3)
+			for j := range clause {
+				clause[j] = sat.Literal(random.Intn(cnf.VariablesCount) + 1)
+				if random.Intn(2) == 0 {
+					clause[j] = -clause[j]
+				}
+			}
+			cnf.Add(clause...)
+		}
+		s := sat.NewSolver(cnf)
+		found, err := s.Solve(context.Background())
+		require.NoError(t, err)
+		require.Equal(t, satisfiable(cnf), found, "formula %d", i)
+		if found {
+			assert.True(t, satisfies(s.Model(), cnf))
+		} else {
+			assert.Nil(t, s.Model())
+		}
+	}
+}
+
+// pigeonhole returns formula which is satisfied if n+1 pigeons are in n holes,
+// at most one pigeon in each hole - it is unsatisfiable and hard for resolution.
+func pigeonhole(n int) *sat.CNF {
+	cnf := &sat.CNF{VariablesCount: (n + 1) * n}
+	variable := func(pigeon, hole int) sat.Literal {
+		return sat.Literal(pigeon*n + hole + 1)
+	}
+	for pigeon := 0; pigeon <= n; pigeon++ {
+		var clause sat.Clause
+		for hole := 0; hole < n; hole++ {
+			clause = append(clause, variable(pigeon, hole))
+		}
+		cnf.Add(clause...)
+	}
+	for hole := 0; hole < n; hole++ {
+		for p1 := 0; p1 <= n; p1++ {
+			for p2 := p1 + 1; p2 <= n; p2++ {
+				cnf.Add(-variable(p1, hole), -variable(p2, hole))
+			}
+		}
+	}
+	return cnf
+}
+
+func TestSolverUnsatisfiable(t *testing.T) {
+	s := sat.NewSolver(pigeonhole(6))
+	found, err := s.Solve(context.Background())
+	require.NoError(t, err)
+	assert.False(t, found)
+	assert.Greater(t, s.Stats().Conflicts, 0)
+
+	cnf := &sat.CNF{VariablesCount: 1}
+	cnf.Add(1)
+	cnf.Add(-1)
+	found, err = sat.NewSolver(cnf).Solve(context.Background())
+	require.NoError(t, err)
+	assert.False(t, found)
+}
+
+func TestSolverIncremental(t *testing.T) {
+	cnf := &sat.CNF{VariablesCount: 3}
+	cnf.Add(1, 2, 3)
+	s := sat.NewSolver(cnf)
+	models := 0
+	for {
+		found, err := s.Solve(context.Background())
+		require.NoError(t, err)
+		if !found {
+			break
+		}
+		models++
+		var exclude sat.Clause
+		for v := 1; v <= 3; v++ {
+			if s.Model()[v] {
+				exclude = append(exclude, -sat.Literal(v))
+			} else {
+				exclude = append(exclude, sat.Literal(v))
+			}
+		}
+		require.NoError(t, s.AddClause(exclude))
+	}
+	assert.Equal(t, 7, models)
+	assert.Error(t, s.AddClause(sat.Clause{4}))
+	assert.Error(t, s.AddClause(sat.Clause{0}))
+}
+
+func TestSolverContext(t *testing.T) {
+	s := sat.NewSolver(pigeonhole(9))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	found, err := s.Solve(ctx)
+	assert.ErrorIs(t, err, context.Canceled)
+	assert.False(t, found)
+}
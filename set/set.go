@@ -2,26 +2,42 @@ package set
 
 import (
 	"fmt"
+	"math/bits"
 	"strings"
 )
 
+const wordSize = 64
+
 // Set is holds some integers, from 1 to provided maxNumber.
 // We could just use map[int]bool, but this implementation provides much better performance
 // for dense dataset with small number of possible values.
-// Thanks to holding all data in consistent slice, it should use cache well.
+// Membership is stored as a bitset - number n is bit (n-1)%64 of word (n-1)/64,
+// so sets of numbers up to 64 (all sudoku sizes up to 64x64) take a single word.
+// Thanks to that, Len, Complement, Intersection, Union and ForEach operate
+// on whole words (using popcount and trailing zeros count) instead of single numbers.
 // In Sudoku solver, we are going to check existence of some number
 // from a very small set (usually 1-9) very often.
 type Set struct {
-	slice []uint8
-	count int
+	words     []uint64
+	maxNumber int
+	// small is storage of words for sets with maxNumber up to 64,
+	// so that creating such set takes only one allocation
+	small [1]uint64
 }
 
 // New creates new Set, which can hold integers from 1 to maxNumber.
 func New(maxNumber int) *Set {
-	return &Set{
-		slice: make([]uint8, maxNumber),
-		count: 0,
+	s := &Set{maxNumber: maxNumber}
+	s.words = s.makeWords()
+	return s
+}
+
+func (s *Set) makeWords() []uint64 {
+	wordsCount := (s.maxNumber + wordSize - 1) / wordSize
+	if wordsCount <= len(s.small) {
+		return s.small[:wordsCount]
 	}
+	return make([]uint64, wordsCount)
 }
 
 // Add tries to add given number (n) to the set.
@@ -29,17 +45,18 @@ func New(maxNumber int) *Set {
 // If n was not in the set, Add adds it and returns true.
 // Argument n must be integer from 1 to maxNumber, otherwise it will panic.
 func (s *Set) Add(n int) bool {
-	if s.slice[n-1] != 0 {
+	word, mask := s.position(n)
+	if s.words[word]&mask != 0 {
 		return false
 	}
-	s.count += 1
-	s.slice[n-1] = 1
+	s.words[word] |= mask
 	return true
 }
 
 // Get argument must be integer from 1 to maxNumber, otherwise it will panic.
 func (s *Set) Get(n int) bool {
-	return s.slice[n-1] != 0
+	word, mask := s.position(n)
+	return s.words[word]&mask != 0
 }
 
 // Remove tries to remove given number (n) from the set.
@@ -47,41 +64,74 @@ func (s *Set) Get(n int) bool {
 // If n was in the set, Remove removes it and returns true.
 // Argument n must be integer from 1 to maxNumber, otherwise it will panic.
 func (s *Set) Remove(n int) bool {
-	if s.slice[n-1] == 0 {
+	word, mask := s.position(n)
+	if s.words[word]&mask == 0 {
 		return false
 	}
-	s.count -= 1
-	s.slice[n-1] = 0
+	s.words[word] &^= mask
 	return true
 }
 
+// position returns index of word and bit mask of number n.
+func (s *Set) position(n int) (int, uint64) {
+	if n < 1 || n > s.maxNumber {
+		panic(fmt.Sprintf("number %d out of set range 1..%d", n, s.maxNumber))
+	}
+	return (n - 1) / wordSize, 1 << uint((n-1)%wordSize)
+}
+
 // Len returns number of elements actually stored in the set (not to be confused with maxNumber).
 func (s *Set) Len() int {
-	return s.count
+	count := 0
+	for _, w := range s.words {
+		count += bits.OnesCount64(w)
+	}
+	return count
 }
 
 // MaxNumber returns maximum number that can be stored in the set.
 // Set can store integers from 1 to MaxNumber. Calling Get / Set / Remove with other value may cause panic.
 func (s *Set) MaxNumber() int {
-	return len(s.slice)
+	return s.maxNumber
 }
 
 // Clear removes all elements from set (underlying storage remains allocated).
 func (s *Set) Clear() {
-	for i := range s.slice {
-		s.slice[i] = 0
+	for i := range s.words {
+		s.words[i] = 0
+	}
+}
+
+// Fill adds all valid values (integers 1..maxNumber) to the set.
+func (s *Set) Fill() {
+	for i := range s.words {
+		s.words[i] = ^uint64(0)
+	}
+	s.clearUnusedBits()
+}
+
+// clearUnusedBits clears bits of the last word which do not represent any number
+// (greater than maxNumber), so that they do not affect Len, ForEach etc.
+func (s *Set) clearUnusedBits() {
+	if rest := s.maxNumber % wordSize; rest != 0 {
+		s.words[len(s.words)-1] &= (1 << uint(rest)) - 1
 	}
-	s.count = 0
 }
 
 // ForEach executes given operation for each set element until
 // it is called for all elements or until operation returns true.
 // It returns last number for which operation was performed,
 // or 0 if set is empty.
+// Operation may remove elements from the set (elements removed before
+// being visited are skipped), but it must not add them.
 func (s *Set) ForEach(operation func(n int) bool) int {
 	lastN := 0
-	for n := 1; n <= s.MaxNumber(); n++ {
-		if s.Get(n) {
+	for i := range s.words {
+		for w := s.words[i]; w != 0; w &= w - 1 {
+			if s.words[i]&(w&-w) == 0 {
+				continue // removed by operation
+			}
+			n := i*wordSize + bits.TrailingZeros64(w) + 1
 			lastN = n
 			if end := operation(n); end {
 				return n
@@ -93,25 +143,69 @@ func (s *Set) ForEach(operation func(n int) bool) int {
 
 // Complement returns set that contains all valid values (integers 1..maxNumber) that are NOT in the set.
 func (s *Set) Complement() *Set {
-	maxNumber := s.MaxNumber()
-	result := New(maxNumber)
-	for n := 1; n <= maxNumber; n++ {
-		if !s.Get(n) {
-			result.Add(n)
+	result := s.Copy()
+	result.Invert()
+	return result
+}
+
+// Invert replaces set with its Complement in place.
+func (s *Set) Invert() {
+	for i := range s.words {
+		s.words[i] = ^s.words[i]
+	}
+	s.clearUnusedBits()
+}
+
+// IntersectWith removes from the set all elements which are not in s2 (in-place Intersection).
+func (s *Set) IntersectWith(s2 *Set) {
+	s.checkCompatible(s2, "IntersectWith")
+	for i := range s.words {
+		s.words[i] &= s2.words[i]
+	}
+}
+
+// UnionWith adds to the set all elements of s2 (in-place Union).
+func (s *Set) UnionWith(s2 *Set) {
+	s.checkCompatible(s2, "UnionWith")
+	for i := range s.words {
+		s.words[i] |= s2.words[i]
+	}
+}
+
+// Subtract removes from the set all elements of s2.
+func (s *Set) Subtract(s2 *Set) {
+	s.checkCompatible(s2, "Subtract")
+	for i := range s.words {
+		s.words[i] &^= s2.words[i]
+	}
+}
+
+// Equal returns true if both sets have the same elements and maxNumber.
+func (s *Set) Equal(s2 *Set) bool {
+	if s.maxNumber != s2.maxNumber {
+		return false
+	}
+	for i := range s.words {
+		if s.words[i] != s2.words[i] {
+			return false
 		}
 	}
-	return result
+	return true
 }
 
-func (s *Set) Copy() *Set {
-	newSlice := make([]uint8, len(s.slice))
-	copy(newSlice, s.slice)
-	return &Set{
-		slice: newSlice,
-		count: s.Len(),
+func (s *Set) checkCompatible(s2 *Set, operation string) {
+	if s.maxNumber != s2.maxNumber {
+		panic(fmt.Sprintf("set %s failed - incompatible maxNumber %d, %d", operation, s.maxNumber, s2.maxNumber))
 	}
 }
 
+func (s *Set) Copy() *Set {
+	result := &Set{maxNumber: s.maxNumber}
+	result.words = result.makeWords()
+	copy(result.words, s.words)
+	return result
+}
+
 // String returns human-readable representation of Set. It should be used only for tests / debugging.
 func (s *Set) String() string {
 	var b strings.Builder
@@ -128,24 +222,9 @@ func Intersection(sets ...*Set) *Set {
 	if len(sets) == 0 {
 		return nil
 	}
-	maxNumber := sets[0].MaxNumber()
-	for _, s := range sets {
-		if s.MaxNumber() != maxNumber {
-			panic(fmt.Sprintf("set Intersection failed - incompatible maxNumber %d, %d", maxNumber, s.MaxNumber()))
-		}
-	}
-	result := New(maxNumber)
-	for n := 1; n <= maxNumber; n++ {
-		ok := true
-		for _, set := range sets {
-			ok = ok && set.Get(n)
-			if !ok {
-				break
-			}
-		}
-		if ok {
-			result.Add(n)
-		}
+	result := sets[0].Copy()
+	for _, s := range sets[1:] {
+		result.IntersectWith(s)
 	}
 	return result
 }
@@ -154,21 +233,9 @@ func Union(sets ...*Set) *Set {
 	if len(sets) == 0 {
 		return nil
 	}
-	maxNumber := sets[0].MaxNumber()
-	for _, s := range sets {
-		if s.MaxNumber() != maxNumber {
-			panic(fmt.Sprintf("set Intersection failed - incompatible maxNumber %d, %d", maxNumber, s.MaxNumber()))
-		}
-	}
-	result := New(maxNumber)
-	for n := 1; n <= maxNumber; n++ {
-		ok := false
-		for _, set := range sets {
-			ok = ok || set.Get(n)
-		}
-		if ok {
-			result.Add(n)
-		}
+	result := sets[0].Copy()
+	for _, s := range sets[1:] {
+		result.UnionWith(s)
 	}
 	return result
 }
@@ -81,6 +81,72 @@ func TestSetComplement(t *testing.T) {
 	assert.True(t, s.Get(3))
 }
 
+func TestSetInPlaceOperations(t *testing.T) {
+	s1 := set.New(4)
+	s1.Add(1)
+	s1.Add(4)
+	s2 := set.New(4)
+	s2.Add(1)
+	s2.Add(2)
+
+	s := s1.Copy()
+	s.UnionWith(s2)
+	assert.Equal(t, "{1,2,4,}", s.String())
+	s.IntersectWith(s1)
+	assert.Equal(t, "{1,4,}", s.String())
+	s.Subtract(s2)
+	assert.Equal(t, "{4,}", s.String())
+	s.Invert()
+	assert.Equal(t, "{1,2,3,}", s.String())
+	s.Fill()
+	assert.Equal(t, 4, s.Len())
+	assert.True(t, s.Equal(set.New(4).Complement()))
+	assert.False(t, s.Equal(s1))
+	assert.False(t, set.New(5).Equal(set.New(4)))
+	assert.Panics(t, func() { s.UnionWith(set.New(5)) })
+}
+
+func TestSetForEach(t *testing.T) {
+	s := set.New(5)
+	assert.Equal(t, 0, s.ForEach(func(int) bool { return false }))
+	s.Add(2)
+	s.Add(3)
+	s.Add(5)
+	var visited []int
+	last := s.ForEach(func(n int) bool {
+		visited = append(visited, n)
+		s.Remove(3) // removed elements are not visited
+		return false
+	})
+	assert.Equal(t, []int{2, 5}, visited)
+	assert.Equal(t, 5, last)
+	assert.Equal(t, 2, s.ForEach(func(int) bool { return true }))
+}
+
+func TestSetLarge(t *testing.T) {
+	for _, maxNumber := range []int{63, 64, 65, 128, 625, 65535} {
+		s := set.New(maxNumber)
+		s.Add(1)
+		s.Add(maxNumber)
+		assert.Equal(t, 2, s.Len())
+		assert.Equal(t, maxNumber, s.ForEach(func(int) bool { return false }))
+		assert.Panics(t, func() { s.Get(maxNumber + 1) })
+
+		complement := s.Complement()
+		assert.Equal(t, maxNumber-2, complement.Len())
+		assert.False(t, complement.Get(maxNumber))
+		assert.True(t, complement.Get(maxNumber-1))
+		assert.Equal(t, 0, set.Intersection(s, complement).Len())
+		assert.Equal(t, maxNumber, set.Union(s, complement).Len())
+
+		c := s.Copy()
+		c.Remove(maxNumber)
+		assert.True(t, s.Get(maxNumber))
+		c.Fill()
+		assert.Equal(t, maxNumber, c.Len())
+	}
+}
+
 func BenchmarkSet(b *testing.B) {
 	var size int = 16
 	s := set.New(size)
@@ -98,6 +98,176 @@ var (
 | 9 1 3 | 7 6 8 | 5 2 4 |
 | 6 2 7 | 4 3 5 | 1 9 8 |
 +-------+-------+-------+
+`)
+
+	// 9x9 boards with dozens of solutions, which require guessing and backtracking to enumerate them
+	boards9x9WithManySolutions = []*board.Board{
+		mustCreateBoard(`3 3
++-------+-------+-------+
+| 0 0 6 | 4 3 0 | 9 0 0 |
+| 8 9 0 | 0 0 1 | 0 7 0 |
+| 0 0 0 | 0 0 5 | 0 2 0 |
++-------+-------+-------+
+| 0 5 7 | 0 0 3 | 8 0 0 |
+| 9 0 0 | 0 0 0 | 0 1 7 |
+| 0 0 2 | 0 7 0 | 0 0 0 |
++-------+-------+-------+
+| 2 0 0 | 0 0 4 | 0 0 0 |
+| 0 0 0 | 0 6 0 | 0 0 0 |
+| 0 3 0 | 0 5 0 | 0 4 9 |
++-------+-------+-------+
+`),
+		mustCreateBoard(`3 3
++-------+-------+-------+
+| 0 2 0 | 0 0 0 | 9 0 8 |
+| 8 9 0 | 0 2 0 | 4 0 3 |
+| 3 0 0 | 0 8 5 | 0 0 0 |
++-------+-------+-------+
+| 4 0 0 | 0 0 0 | 0 6 0 |
+| 0 0 0 | 0 0 0 | 5 0 0 |
+| 0 0 2 | 5 0 8 | 0 9 4 |
++-------+-------+-------+
+| 0 0 0 | 3 0 0 | 0 0 0 |
+| 5 0 0 | 7 0 9 | 0 0 0 |
+| 7 0 1 | 8 0 0 | 0 0 0 |
++-------+-------+-------+
+`),
+		mustCreateBoard(`3 3
++-------+-------+-------+
+| 0 0 6 | 0 0 0 | 0 0 8 |
+| 0 9 5 | 0 2 0 | 0 7 0 |
+| 0 7 0 | 0 0 0 | 1 2 0 |
++-------+-------+-------+
+| 0 0 0 | 0 9 0 | 8 6 0 |
+| 9 0 0 | 0 4 0 | 0 0 7 |
+| 0 0 2 | 0 0 0 | 0 9 0 |
++-------+-------+-------+
+| 0 6 0 | 3 1 0 | 7 0 0 |
+| 5 0 8 | 0 0 0 | 2 0 0 |
+| 0 0 0 | 8 0 0 | 0 0 9 |
++-------+-------+-------+
+`),
+	}
+
+	// jigsaw boards:
+
+	jigsaw6x6 = mustCreateBoard(`jigsaw 6
++---------------+---+---+
+| 0   0   0   0 | 0 | 0 |
++       +---+---+   +   +
+| 0   6 | 1 | 2 | 0 | 0 |
++-------+---+   +   +   +
+| 3   0   0 | 0 | 0 | 5 |
++           +   +   +---+
+| 0   0   0 | 0 | 0   0 |
++-------+---+---+-------+
+| 0   0 | 0   5   0   0 |
++       +   +-------+   +
+| 0   0 | 0 | 0   0 | 3 |
++-------+---+-------+---+
+regions:
+0 0 0 0 1 3
+0 0 1 3 1 3
+2 2 2 3 1 3
+2 2 2 3 1 1
+4 4 5 5 5 5
+4 4 5 4 4 5
+`)
+
+	jigsaw6x6Solution = mustCreateBoard(`jigsaw 6
++---------------+---+---+
+| 1   2   3   4 | 5 | 6 |
++       +---+---+   +   +
+| 5   6 | 1 | 2 | 3 | 4 |
++-------+---+   +   +   +
+| 3   4   2 | 1 | 6 | 5 |
++           +   +   +---+
+| 6   1   5 | 3 | 4   2 |
++-------+---+---+-------+
+| 4   3 | 6   5   2   1 |
++       +   +-------+   +
+| 2   5 | 4 | 6   1 | 3 |
++-------+---+-------+---+
+regions:
+0 0 0 0 1 3
+0 0 1 3 1 3
+2 2 2 3 1 3
+2 2 2 3 1 1
+4 4 5 5 5 5
+4 4 5 4 4 5
+`)
+
+	// diagonal (X-Sudoku) boards:
+
+	diagonal6x6 = mustCreateBoard(`3 2 diagonal
++-------+-------+
+| 0 1 0 | 0 0 0 |
+| 4 0 0 | 0 0 0 |
++-------+-------+
+| 0 0 0 | 0 0 0 |
+| 0 0 0 | 0 5 0 |
++-------+-------+
+| 0 6 0 | 0 0 0 |
+| 2 0 0 | 0 0 0 |
++-------+-------+
+`)
+
+	diagonal6x6Solution = mustCreateBoard(`3 2 diagonal
++-------+-------+
+| 3 1 6 | 4 2 5 |
+| 4 5 2 | 6 1 3 |
++-------+-------+
+| 5 2 1 | 3 6 4 |
+| 6 3 4 | 2 5 1 |
++-------+-------+
+| 1 6 3 | 5 4 2 |
+| 2 4 5 | 1 3 6 |
++-------+-------+
+`)
+
+	// killer boards:
+
+	killer6x6 = mustCreateBoard(`3 2
++-------+-------+
+| 0 0 0 | 0 0 0 |
+| 0 0 0 | 0 0 0 |
++-------+-------+
+| 0 0 0 | 0 0 0 |
+| 0 0 0 | 0 0 0 |
++-------+-------+
+| 0 0 0 | 0 0 0 |
+| 0 0 0 | 0 0 0 |
++-------+-------+
+cages:
+6: (1, 4) (2, 4) (2, 5)
+10: (1, 5) (0, 5)
+6: (4, 3) (4, 4)
+8: (0, 0) (1, 0)
+17: (5, 2) (4, 2) (4, 1) (3, 1)
+4: (4, 5) (3, 5)
+13: (2, 1) (1, 1) (0, 1) (2, 0)
+9: (0, 3) (0, 2) (1, 2)
+5: (0, 4)
+10: (4, 0) (5, 0) (5, 1)
+7: (5, 4) (5, 5)
+12: (3, 2) (2, 2) (2, 3)
+9: (3, 4) (3, 3)
+5: (1, 3)
+1: (5, 3)
+4: (3, 0)
+`)
+
+	killer6x6Solution = mustCreateBoard(`3 2
++-------+-------+
+| 2 6 5 | 4 1 3 |
+| 1 3 4 | 2 5 6 |
++-------+-------+
+| 3 2 1 | 5 6 4 |
+| 4 5 6 | 3 2 1 |
++-------+-------+
+| 5 1 3 | 6 4 2 |
+| 6 4 2 | 1 3 5 |
++-------+-------+
 `)
 
 	// boards for benchmarks:
@@ -164,6 +334,54 @@ var (
 |  0  8  0 |  0  0 10 |  0  5  4 |  0  7  0 |
 |  0  0  0 |  5  0  0 |  0 10  0 |  4  0  6 |
 +----------+----------+----------+----------+
+`)
+
+	board16x16 = mustCreateBoard(`4 4
++-------------+-------------+-------------+-------------+
+|  0  0  0  2 | 16  8  7  0 |  0  0  0  0 | 10  5  0  6 |
+|  0  0  0  0 | 13  0  0 15 |  0  4  0  0 |  0  0  0  0 |
+|  0 14 12  0 |  0 11  0  0 |  3  2  0  0 |  7 16  0  0 |
+|  0  5  4  0 |  3  1  6  0 |  0 14  0 15 |  0  0  8  0 |
++-------------+-------------+-------------+-------------+
+|  0  0  0  0 |  6  0  0  0 |  0  0  0 16 |  0  0  0  0 |
+| 14  0  8  9 |  0  0 12  5 |  0  0  0  0 |  0  0  0 10 |
+| 10  0  0  4 |  0  0  0  0 | 11  6  0  5 |  0  2  1  0 |
+|  0 11  6  5 |  0  0  0  0 |  0  8  0  0 |  0 14  0  0 |
++-------------+-------------+-------------+-------------+
+| 13  0  0  0 |  0  0  0  0 |  8  0  4  0 |  2  0  0  5 |
+|  0  9  0 15 |  0  5  0  3 |  0  0  0  6 | 11  0 10  0 |
+|  0  0  0 16 |  0  4  0  8 |  0 15  0  0 |  0  9  0  0 |
+|  4  3  0  0 | 10  0  0  7 |  2  0  1 11 | 14  0  0  0 |
++-------------+-------------+-------------+-------------+
+| 16 15  9  0 |  0  0  0 11 |  0  0  0  0 |  0  0 13  0 |
+|  0  0 14  0 |  0  0  3  0 |  7  0 13  0 |  9  0  0 12 |
+| 11  0  0  0 |  8  0  0  0 | 14  0  0  0 |  3 10  0  1 |
+|  0  7  3  0 |  0  0  0  0 |  0  9 10  0 |  5  0  0  0 |
++-------------+-------------+-------------+-------------+
+`)
+
+	board16x16Solution = mustCreateBoard(`4 4
++-------------+-------------+-------------+-------------+
+|  3  1 15  2 | 16  8  7  4 |  9 12 11 13 | 10  5 14  6 |
+|  8 16 10 11 | 13 12 14 15 |  6  4  5  7 |  1  3  9  2 |
+|  6 14 12 13 |  9 11  5 10 |  3  2  8  1 |  7 16  4 15 |
+|  9  5  4  7 |  3  1  6  2 | 10 14 16 15 | 12 13  8 11 |
++-------------+-------------+-------------+-------------+
+| 15  2  1  3 |  6 10  4 14 | 13  7 12 16 |  8 11  5  9 |
+| 14 13  8  9 | 11 16 12  5 | 15  1  2  3 |  6  4  7 10 |
+| 10 12 16  4 |  7  9  8 13 | 11  6 14  5 | 15  2  1  3 |
+|  7 11  6  5 | 15  3  2  1 |  4  8  9 10 | 16 14 12 13 |
++-------------+-------------+-------------+-------------+
+| 13  6  7 14 | 12 15 11 16 |  8 10  4  9 |  2  1  3  5 |
+|  1  9  2 15 | 14  5 13  3 | 12 16  7  6 | 11  8 10  4 |
+| 12 10 11 16 |  2  4  1  8 |  5 15  3 14 | 13  9  6  7 |
+|  4  3  5  8 | 10  6  9  7 |  2 13  1 11 | 14 12 15 16 |
++-------------+-------------+-------------+-------------+
+| 16 15  9 12 |  5 14 10 11 |  1  3  6  2 |  4  7 13  8 |
+|  5  8 14 10 |  1  2  3  6 |  7 11 13  4 |  9 15 16 12 |
+| 11  4 13  6 |  8  7 16  9 | 14  5 15 12 |  3 10  2  1 |
+|  2  7  3  1 |  4 13 15 12 | 16  9 10  8 |  5  6 11 14 |
++-------------+-------------+-------------+-------------+
 `)
 
 	board25x25 = mustCreateBoard(`5 5
@@ -1,6 +1,7 @@
 package solver
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/tomaszmj/sudoku/board"
@@ -46,15 +47,34 @@ func (b *bruteforce) Reset(board *board.Board) {
 // If solution is new, it is saved in b.solutions and returned.
 // Otherwise, nil is returned.
 func (b *bruteforce) NextSolution() *board.Board {
+	solution, _ := b.NextSolutionContext(context.Background())
+	return solution
+}
+
+// NextSolutionContext can be called again after the search has been interrupted,
+// but the search is restarted from scratch (only solutions that have already
+// been returned are remembered).
+func (b *bruteforce) NextSolutionContext(ctx context.Context) (*board.Board, error) {
+	solution, err := b.search(ctx)
+	if err != nil {
+		b.clearFieldsToFill()
+	}
+	return solution, err
+}
+
+func (b *bruteforce) search(ctx context.Context) (*board.Board, error) {
+	if err := contextDone(ctx); err != nil {
+		return nil, err
+	}
 	for field := range b.fieldsToFill {
 		if b.board.Get(field.x, field.y) != 0 {
 			continue
 		}
 		for x := 1; x <= b.board.Size(); x++ {
 			b.board.Set(field.x, field.y, uint16(x))
-			ns := b.NextSolution()
-			if ns != nil {
-				return ns // return from recursive call
+			ns, err := b.search(ctx)
+			if ns != nil || err != nil {
+				return ns, err // return from recursive call
 			}
 			b.board.Set(field.x, field.y, 0)
 		}
@@ -62,10 +82,10 @@ func (b *bruteforce) NextSolution() *board.Board {
 	// break recursion and validate board state if all fieldsToFill are already set
 	if b.boardIsValid() {
 		if b.recordSolution() {
-			return b.solutions[len(b.solutions)-1]
+			return b.solutions[len(b.solutions)-1], nil
 		}
 	}
-	return nil
+	return nil, nil
 }
 
 // recordSolution checks if given solution has been encountered before.
@@ -78,10 +98,14 @@ func (b *bruteforce) recordSolution() bool {
 		}
 	}
 	b.solutions = append(b.solutions, b.board.Copy())
+	b.clearFieldsToFill()
+	return true
+}
+
+func (b *bruteforce) clearFieldsToFill() {
 	for field := range b.fieldsToFill {
 		b.board.Set(field.x, field.y, 0)
 	}
-	return true
 }
 
 func (b *bruteforce) boardIsValid() bool {
@@ -0,0 +1,120 @@
+package solver_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tomaszmj/sudoku/board"
+	"github.com/tomaszmj/sudoku/solver"
+)
+
+// evenFields is a constraint without candidates removal - all its fields must have even numbers.
+type evenFields []board.Field
+
+func (e evenFields) Fields() []board.Field {
+	return e
+}
+
+func (e evenFields) Check(b *board.Board) error {
+	for _, f := range e {
+		if n := b.Get(f.X, f.Y); n%2 != 0 {
+			return fmt.Errorf("number %d on (%d, %d) is not even", n, f.X, f.Y)
+		}
+	}
+	return nil
+}
+
+// fieldsSum is a constraint which depends on more than 2 fields - numbers on its fields must add up to sum.
+type fieldsSum struct {
+	fields []board.Field
+	sum    int
+}
+
+func (s fieldsSum) Fields() []board.Field {
+	return s.fields
+}
+
+func (s fieldsSum) Check(b *board.Board) error {
+	sum := 0
+	for _, f := range s.fields {
+		n := b.Get(f.X, f.Y)
+		if n == 0 {
+			return nil
+		}
+		sum += int(n)
+	}
+	if sum != s.sum {
+		return fmt.Errorf("numbers add up to %d instead of %d", sum, s.sum)
+	}
+	return nil
+}
+
+func TestConstraints(t *testing.T) {
+	countAll := func(t *testing.T, s solver.Solver, b *board.Board) int {
+		s.Reset(b)
+		count := 0
+		for solution := s.NextSolution(); solution != nil; solution = s.NextSolution() {
+			require.NoError(t, solution.CheckRules())
+			count++
+		}
+		return count
+	}
+
+	t.Run("thermometer", func(t *testing.T) {
+		b, err := board.New(2, 2)
+		require.NoError(t, err)
+		require.NoError(t, b.AddConstraint(board.Thermometer{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 2, Y: 0}, {X: 3, Y: 0}}))
+		// the first row must be 1 2 3 4, that is 1 of 4! permutations of all 288 grids
+		assert.Equal(t, 12, countAll(t, solver.NewSmartBarcktrack(), b))
+		assert.Equal(t, 12, countAll(t, solver.NewDLX(), b))
+		assert.Equal(t, 12, countAll(t, solver.NewSAT(), b))
+		assert.Equal(t, 12, solver.CountSolutions(b, 0))
+	})
+
+	t.Run("kropki dots", func(t *testing.T) {
+		b, err := board.New(2, 2)
+		require.NoError(t, err)
+		require.NoError(t, b.AddConstraint(board.KropkiDot{A: board.Field{X: 0, Y: 0}, B: board.Field{X: 1, Y: 0}, Black: true}))
+		require.NoError(t, b.AddConstraint(board.KropkiDot{A: board.Field{X: 1, Y: 0}, B: board.Field{X: 2, Y: 0}, Black: true}))
+		require.NoError(t, b.AddConstraint(board.KropkiDot{A: board.Field{X: 0, Y: 1}, B: board.Field{X: 0, Y: 2}}))
+		s := solver.NewSmartBarcktrack()
+		s.Reset(b)
+		solution := s.NextSolution()
+		require.NotNil(t, solution)
+		assert.Equal(t, uint16(2), solution.Get(1, 0)) // 1 2 4 or 4 2 1
+		assert.Equal(t, countAll(t, solver.NewDLX(), b), countAll(t, s, b))
+		assert.Equal(t, countAll(t, solver.NewDLX(), b), countAll(t, solver.NewSAT(), b))
+	})
+
+	t.Run("constraint without candidates removal", func(t *testing.T) {
+		b, err := board.New(2, 2)
+		require.NoError(t, err)
+		require.NoError(t, b.AddConstraint(evenFields{{X: 0, Y: 0}, {X: 1, Y: 1}, {X: 2, Y: 2}}))
+		count := countAll(t, solver.NewSmartBarcktrack(), b)
+		assert.Greater(t, count, 0)
+		assert.Less(t, count, 288)
+		assert.Equal(t, countAll(t, solver.NewDLX(), b), count)
+		assert.Equal(t, countAll(t, solver.NewSAT(), b), count)
+
+		// bruteforce checks constraints with board.Validate
+		solution := solver.NewDLX()
+		solution.Reset(b)
+		puzzle := solution.NextSolution()
+		require.NotNil(t, puzzle)
+		puzzle.Set(0, 0, 0)
+		puzzle.Set(1, 1, 0)
+		assert.Equal(t, 1, countAll(t, solver.NewBruteforce(), puzzle))
+	})
+
+	t.Run("constraint which cannot be encoded in SAT exactly", func(t *testing.T) {
+		b, err := board.New(2, 2)
+		require.NoError(t, err)
+		require.NoError(t, b.AddConstraint(fieldsSum{fields: []board.Field{{X: 0, Y: 0}, {X: 1, Y: 1}, {X: 2, Y: 2}}, sum: 10}))
+		count := countAll(t, solver.NewDLX(), b)
+		assert.Greater(t, count, 0)
+		assert.Less(t, count, 288)
+		assert.Equal(t, count, countAll(t, solver.NewSAT(), b))
+	})
+}
@@ -0,0 +1,52 @@
+package solver
+
+import (
+	"context"
+
+	"github.com/tomaszmj/sudoku/board"
+)
+
+// CountSolutions returns number of solutions of the board, but it stops counting
+// as soon as limit is reached (so it returns at most limit). If limit is not positive,
+// all solutions are counted. Unlike calling NextSolution in a loop, it does not copy
+// the board for each solution found.
+func CountSolutions(b *board.Board, limit int) int {
+	s := &smartBacktrack{}
+	s.Reset(b)
+	count := 0
+	for limit <= 0 || count < limit {
+		found := false
+		// search cannot fail with context.Background
+		_ = s.search(context.Background(), func(*board.Board) {
+			found = true
+		})
+		if !found {
+			break
+		}
+		count++
+	}
+	return count
+}
+
+// IsUnique returns true if the board has exactly one solution.
+func IsUnique(b *board.Board) bool {
+	return CountSolutions(b, 2) == 1
+}
+
+// CountMultiGridSolutions works just like CountSolutions, but for multi-grid puzzles
+// (see MultiGrid).
+func CountMultiGridSolutions(m *board.MultiGrid, limit int) int {
+	s := NewMultiGrid()
+	s.Reset(m)
+	count := 0
+	for limit <= 0 || count < limit {
+		// with context.Background error is returned only if the puzzle breaks
+		// sudoku rules, then found is false
+		found, _ := s.dlx.searchContext(context.Background())
+		if !found {
+			break
+		}
+		count++
+	}
+	return count
+}
@@ -0,0 +1,37 @@
+package solver_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tomaszmj/sudoku/board"
+	"github.com/tomaszmj/sudoku/solver"
+)
+
+func TestCountSolutions(t *testing.T) {
+	empty4x4, err := board.New(2, 2)
+	require.NoError(t, err)
+	assert.Equal(t, 1, solver.CountSolutions(solvedBoard, 0))
+	assert.Equal(t, 1, solver.CountSolutions(boardToSolve, 10))
+	assert.Equal(t, 0, solver.CountSolutions(unsolveableBoard, 10))
+	assert.Equal(t, 0, solver.CountSolutions(invalidBoard, 10))
+	assert.Equal(t, 2, solver.CountSolutions(boardWithManySoltions, 0))
+	assert.Equal(t, 1, solver.CountSolutions(boardWithManySoltions, 1))
+	assert.Equal(t, 288, solver.CountSolutions(empty4x4, 0))
+	assert.Equal(t, 100, solver.CountSolutions(empty4x4, 100))
+	assert.Equal(t, 1, solver.CountSolutions(difficultBoard, 2))
+}
+
+func TestIsUnique(t *testing.T) {
+	assert.True(t, solver.IsUnique(boardToSolve))
+	assert.True(t, solver.IsUnique(difficultBoard))
+	assert.False(t, solver.IsUnique(boardWithManySoltions))
+	assert.False(t, solver.IsUnique(unsolveableBoard))
+}
+
+func BenchmarkCountSolutions(b *testing.B) {
+	for i := 0; i < b.N; i++ {
+		solver.CountSolutions(board25x25, 10)
+	}
+}
@@ -0,0 +1,70 @@
+package solver_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tomaszmj/sudoku/internal/testutil"
+	"github.com/tomaszmj/sudoku/solver"
+)
+
+func TestDiagonal(t *testing.T) {
+	solvers := map[string]solver.Solver{
+		"smartBacktrack": solver.NewSmartBarcktrack(),
+		"dlx":            solver.NewDLX(),
+		"sat":            solver.NewSAT(),
+	}
+	for name, s := range solvers {
+		t.Run(name, func(t *testing.T) {
+			s.Reset(diagonal6x6)
+			solution := s.NextSolution()
+			require.NotNil(t, solution)
+			assert.Equal(t, diagonal6x6Solution.String(), solution.String())
+			assert.True(t, solution.Equal(diagonal6x6Solution))
+			assert.Nil(t, s.NextSolution())
+		})
+	}
+
+	t.Run("9x9 board from file", func(t *testing.T) {
+		puzzle := testutil.ReadBoard(t, "../cmd/boards/diagonal9x9.txt")
+		expected := testutil.ReadBoard(t, "../cmd/boards/diagonal9x9_solution.txt")
+		assert.True(t, expected.IsDiagonal())
+		for name, s := range solvers {
+			s.Reset(puzzle)
+			solution := s.NextSolution()
+			require.NotNil(t, solution, name)
+			assert.True(t, solution.Equal(expected), name)
+		}
+	})
+
+	t.Run("bruteforce", func(t *testing.T) {
+		puzzle := diagonal6x6Solution.Copy()
+		puzzle.Set(0, 0, 0)
+		puzzle.Set(2, 3, 0)
+		puzzle.Set(5, 0, 0)
+		puzzle.Set(4, 4, 0)
+		s := solver.NewBruteforce()
+		s.Reset(puzzle)
+		solution := s.NextSolution()
+		require.NotNil(t, solution)
+		assert.True(t, solution.Equal(diagonal6x6Solution))
+		assert.Nil(t, s.NextSolution())
+	})
+
+	t.Run("count solutions", func(t *testing.T) {
+		assert.True(t, solver.IsUnique(diagonal6x6))
+		// without diagonal constraint the puzzle is ambiguous
+		regular := diagonal6x6.Copy()
+		regular.SetDiagonal(false)
+		assert.False(t, solver.IsUnique(regular))
+	})
+
+	t.Run("diagonal rules are validated", func(t *testing.T) {
+		invalid := diagonal6x6.Copy()
+		invalid.Set(5, 5, 4) // the same as in 1, 1
+		s := solver.NewSmartBarcktrack()
+		s.Reset(invalid)
+		assert.Nil(t, s.NextSolution())
+	})
+}
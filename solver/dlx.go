@@ -0,0 +1,380 @@
+package solver
+
+import (
+	"context"
+	"time"
+
+	"github.com/tomaszmj/sudoku/board"
+)
+
+// dlx solver uses Knuth's Algorithm X with "dancing links" (DLX).
+// Sudoku is transformed into exact cover problem:
+// - each row of the exact cover matrix is a candidate (number n placed on field x, y),
+// - each column is a constraint that must be satisfied exactly once: every field
+// must have some number and every unit (row, column, subgrid) must have every number.
+// Solution is a set of rows (candidates) that covers each column exactly once.
+//
+// The matrix is sparse, so it is stored as a toroidal doubly-linked list of nodes
+// (only 1s in the matrix are stored). Removing and restoring column (cover / uncover)
+// costs only relinking neighbouring nodes. Nodes are kept in slices and linked by indexes.
+// Node 0 is the root, nodes 1..columnsCount are column headers, the rest are matrix 1s.
+//
+// As in smartBacktrack, search is implemented with explicit stack instead of recursion,
+// so that solutions can be generated one-by-one by NextSolution.
+// Numbers present on the initial board are selected (their columns are covered) in Reset,
+// so the search explores only candidates which do not conflict with them.
+// Killer Sudoku cages and constraints (see board.Constraint) are not part of the exact cover problem -
+// solutions which break them are just skipped, so dlx is not efficient for such puzzles.
+type dlx struct {
+	board                 *board.Board
+	checkSolution         func() bool // optional check of solution found, for constraints not included in the matrix
+	left, right, up, down []int
+	column                []int         // column header of each node
+	size                  []int         // number of nodes in each column (valid only for column headers)
+	candidates            []fieldChoice // candidate represented by matrix row of each node
+	stack                 []int         // nodes of rows selected during search
+	guessed               []bool        // for each row in stack - if it was chosen among many rows
+	depth                 int           // number of guesses in stack
+	resume                bool          // solution has been returned, so the search must be resumed with backtracking
+	solvable              bool
+	stats                 Stats
+	elapsed               time.Duration // time spent on searching since the last solution was found
+	hook                  Hook
+	err                   error // validation error of the initial board
+}
+
+// NewDLX returns Solver based on Knuth's Algorithm X with dancing links.
+func NewDLX() Solver {
+	return &dlx{}
+}
+
+func (d *dlx) Reset(b *board.Board) {
+	d.err = b.CheckRules()
+	if d.err != nil {
+		d.solvable = false
+		return
+	}
+	d.board = b.Copy()
+	d.checkSolution = nil
+	if len(b.Cages()) > 0 || len(b.Constraints()) > 0 {
+		d.checkSolution = func() bool {
+			return d.solution().CheckRules() == nil
+		}
+	}
+	size := b.Size()
+	var fields []board.Field
+	var given []uint16
+	b.ForEach(func(x, y int, n uint16) {
+		fields = append(fields, board.Field{X: x, Y: y})
+		given = append(given, n)
+	})
+	var units [][]int
+	for _, unit := range b.Units() {
+		indexes := make([]int, 0, len(unit.Fields))
+		for _, f := range unit.Fields {
+			indexes = append(indexes, f.Y*size+f.X)
+		}
+		units = append(units, indexes)
+	}
+	d.init(size, fields, given, units)
+}
+
+// init builds exact cover matrix for the puzzle, in which given fields must be filled
+// with numbers from 1 to size, so that each unit (list of indexes of fields)
+// contains each number exactly once. Numbers given on fields (non-zero) are selected.
+func (d *dlx) init(size int, fields []board.Field, given []uint16, units [][]int) {
+	d.stats = Stats{}
+	d.elapsed = 0
+	d.stack = d.stack[:0]
+	d.guessed = d.guessed[:0]
+	d.depth = 0
+	d.resume = false
+	d.err = nil
+	d.solvable = true
+
+	unitsOfField := make([][]int, len(fields))
+	for i, unit := range units {
+		for _, field := range unit {
+			unitsOfField[field] = append(unitsOfField[field], i)
+		}
+	}
+	// columns 1..len(fields) are "field is filled" constraints,
+	// the rest are "unit has number" constraints
+	columnsCount := len(fields) + len(units)*size
+	d.initColumns(columnsCount)
+
+	var givenRows []int
+	for i, f := range fields {
+		n := given[i]
+		for candidate := 1; candidate <= size; candidate++ {
+			if n != 0 && uint16(candidate) != n {
+				continue
+			}
+			columns := make([]int, 0, len(unitsOfField[i])+1)
+			columns = append(columns, i+1)
+			for _, unitIndex := range unitsOfField[i] {
+				columns = append(columns, len(fields)+unitIndex*size+candidate)
+			}
+			row := d.addRow(columns, fieldChoice{f.X, f.Y, uint16(candidate)})
+			if n != 0 {
+				givenRows = append(givenRows, row)
+			}
+		}
+	}
+	for _, row := range givenRows {
+		d.cover(d.column[row])
+		d.selectRow(row)
+	}
+}
+
+func (d *dlx) NextSolution() *board.Board {
+	solution, _ := d.NextSolutionContext(context.Background())
+	return solution
+}
+
+// NextSolutionContext can be called again after the search has been interrupted -
+// it is resumed from the place where it was stopped.
+func (d *dlx) NextSolutionContext(ctx context.Context) (*board.Board, error) {
+	found, err := d.searchContext(ctx)
+	if err != nil || !found {
+		return nil, err
+	}
+	return d.solution(), nil
+}
+
+// searchContext is search, which additionally checks d.err and d.solvable
+// (it is common part of NextSolutionContext of solvers based on dlx).
+func (d *dlx) searchContext(ctx context.Context) (bool, error) {
+	if d.err != nil {
+		return false, d.err
+	}
+	if !d.solvable {
+		return false, nil
+	}
+	found, err := d.search(ctx)
+	if err != nil {
+		return false, err
+	}
+	if !found {
+		d.solvable = false
+	}
+	return found, nil
+}
+
+// solution returns board with rows selected in d.stack.
+func (d *dlx) solution() *board.Board {
+	solution := d.board.Copy()
+	for _, node := range d.stack {
+		c := d.candidates[node]
+		solution.Set(c.x, c.y, c.n)
+	}
+	return solution
+}
+
+func (d *dlx) Stats() Stats {
+	return d.stats
+}
+
+func (d *dlx) SetHook(hook Hook) {
+	d.hook = hook
+}
+
+// event sends e to the hook (if it is set).
+func (d *dlx) event(e Event) {
+	if d.hook != nil {
+		d.hook(e)
+	}
+}
+
+// placed updates statistics and sends event after the last row in stack has been selected.
+func (d *dlx) placed() {
+	last := len(d.stack) - 1
+	c := d.candidates[d.stack[last]]
+	d.stats.Nodes++
+	if !d.guessed[last] {
+		d.stats.Propagations++
+	}
+	d.stats.MaxDepth = maxInt(d.stats.MaxDepth, d.depth)
+	d.event(Event{Type: EventPlace, X: c.x, Y: c.y, Number: c.n, Guess: d.guessed[last], Depth: d.depth})
+}
+
+// search looks for the next solution. If it is found, it returns true
+// and rows selected in d.stack are the solution.
+// If ctx is done, search can be continued by calling it again.
+func (d *dlx) search(ctx context.Context) (bool, error) {
+	start := time.Now()
+	defer func() {
+		d.elapsed += time.Since(start)
+	}()
+	if d.resume {
+		d.resume = false
+		if !d.advance() {
+			return false, nil
+		}
+	}
+	for i := 0; ; i++ {
+		if i%contextCheckInterval == 0 {
+			if err := contextDone(ctx); err != nil {
+				return false, err
+			}
+		}
+		if d.right[0] == 0 { // all columns are covered
+			if d.checkSolution != nil && !d.checkSolution() {
+				if !d.advance() {
+					return false, nil
+				}
+				continue
+			}
+			d.resume = true
+			// only time spent after finding the solution is counted for the next one (see smartBacktrack.search)
+			d.stats.SolutionTimes = append(d.stats.SolutionTimes, d.elapsed+time.Since(start))
+			d.elapsed = -time.Since(start)
+			d.event(Event{Type: EventSolution, Depth: d.depth})
+			return true, nil
+		}
+		c := d.chooseColumn()
+		if d.size[c] == 0 {
+			if !d.advance() {
+				return false, nil
+			}
+			continue
+		}
+		guess := d.size[c] > 1
+		if guess {
+			d.stats.Guesses++
+			d.depth++
+		}
+		d.cover(c)
+		row := d.down[c]
+		d.stack = append(d.stack, row)
+		d.guessed = append(d.guessed, guess)
+		d.selectRow(row)
+		d.placed()
+	}
+}
+
+// advance backtracks to the nearest row which has not been tried yet and selects it.
+// It returns false if there are no such rows (the whole search tree has been explored).
+func (d *dlx) advance() bool {
+	for len(d.stack) > 0 {
+		last := len(d.stack) - 1
+		row := d.stack[last]
+		d.unselectRow(row)
+		if d.guessed[last] {
+			d.depth--
+		}
+		removed := d.candidates[row]
+		d.event(Event{Type: EventBacktrack, X: removed.x, Y: removed.y, Number: removed.n, Depth: d.depth})
+		c := d.column[row]
+		row = d.down[row]
+		if row != c {
+			// there was more than one row in the column, so the row chosen before was a guess
+			d.stats.Backtracks++
+			d.depth++
+			d.stack[last] = row
+			d.selectRow(row)
+			d.placed()
+			return true
+		}
+		d.stack = d.stack[:last]
+		d.guessed = d.guessed[:last]
+		d.uncover(c)
+	}
+	return false
+}
+
+// chooseColumn returns column with the smallest number of rows,
+// which limits branching of the search tree.
+func (d *dlx) chooseColumn() int {
+	best := d.right[0]
+	for c := d.right[best]; c != 0; c = d.right[c] {
+		if d.size[c] < d.size[best] {
+			best = c
+		}
+	}
+	return best
+}
+
+func (d *dlx) initColumns(columnsCount int) {
+	nodes := columnsCount + 1
+	d.left = make([]int, nodes)
+	d.right = make([]int, nodes)
+	d.up = make([]int, nodes)
+	d.down = make([]int, nodes)
+	d.column = make([]int, nodes)
+	d.size = make([]int, nodes)
+	d.candidates = make([]fieldChoice, nodes)
+	for i := 0; i < nodes; i++ {
+		d.left[i] = (i + nodes - 1) % nodes
+		d.right[i] = (i + 1) % nodes
+		d.up[i] = i
+		d.down[i] = i
+		d.column[i] = i
+	}
+}
+
+// addRow appends row with 1s in given columns and returns index of its first node.
+func (d *dlx) addRow(columns []int, candidate fieldChoice) int {
+	first := len(d.left)
+	for i, c := range columns {
+		node := first + i
+		left, right := node-1, node+1
+		if i == 0 {
+			left = first + len(columns) - 1
+		}
+		if i == len(columns)-1 {
+			right = first
+		}
+		d.left = append(d.left, left)
+		d.right = append(d.right, right)
+		d.up = append(d.up, d.up[c])
+		d.down = append(d.down, c)
+		d.column = append(d.column, c)
+		d.size = append(d.size, 0)
+		d.candidates = append(d.candidates, candidate)
+		d.down[d.up[c]] = node
+		d.up[c] = node
+		d.size[c]++
+	}
+	return first
+}
+
+// cover removes column c from header list and all rows that have 1 in column c from other columns.
+func (d *dlx) cover(c int) {
+	d.right[d.left[c]] = d.right[c]
+	d.left[d.right[c]] = d.left[c]
+	for i := d.down[c]; i != c; i = d.down[i] {
+		for j := d.right[i]; j != i; j = d.right[j] {
+			d.down[d.up[j]] = d.down[j]
+			d.up[d.down[j]] = d.up[j]
+			d.size[d.column[j]]--
+		}
+	}
+}
+
+// uncover reverts cover(c) - it must be called in reversed order of cover calls.
+func (d *dlx) uncover(c int) {
+	for i := d.up[c]; i != c; i = d.up[i] {
+		for j := d.left[i]; j != i; j = d.left[j] {
+			d.size[d.column[j]]++
+			d.down[d.up[j]] = j
+			d.up[d.down[j]] = j
+		}
+	}
+	d.right[d.left[c]] = c
+	d.left[d.right[c]] = c
+}
+
+// selectRow covers all columns of given row, except for column of the row node itself
+// (which must have been covered before).
+func (d *dlx) selectRow(row int) {
+	for j := d.right[row]; j != row; j = d.right[j] {
+		d.cover(d.column[j])
+	}
+}
+
+func (d *dlx) unselectRow(row int) {
+	for j := d.left[row]; j != row; j = d.left[j] {
+		d.uncover(d.column[j])
+	}
+}
@@ -15,6 +15,7 @@ type fieldChoice struct {
 type fieldToFill struct {
 	x, y           int
 	possibleValues *set.Set
+	index          int // index in fieldsToFillHeap (maintained by the heap), -1 if the field is not in the heap
 }
 
 // String is used only for test
@@ -22,7 +23,9 @@ func (f fieldToFill) String() string {
 	return fmt.Sprintf("(%d, %d) %s", f.x, f.y, f.possibleValues.String())
 }
 
-type fieldsToFillHeap []fieldToFill
+// fieldsToFillHeap keeps index of each field up to date, so that heap.Fix
+// or heap.Remove can be called for a field whose possibleValues have changed.
+type fieldsToFillHeap []*fieldToFill
 
 func (h fieldsToFillHeap) Len() int {
 	return len(h)
@@ -34,16 +37,22 @@ func (h fieldsToFillHeap) Less(i, j int) bool {
 
 func (h fieldsToFillHeap) Swap(i, j int) {
 	h[i], h[j] = h[j], h[i]
+	h[i].index = i
+	h[j].index = j
 }
 
 func (h *fieldsToFillHeap) Push(x interface{}) {
-	*h = append(*h, x.(fieldToFill))
+	f := x.(*fieldToFill)
+	f.index = len(*h)
+	*h = append(*h, f)
 }
 
 func (h *fieldsToFillHeap) Pop() interface{} {
 	old := *h
 	n := len(old)
 	x := old[n-1]
+	old[n-1] = nil
+	x.index = -1
 	*h = old[0 : n-1]
 	return x
 }
@@ -11,31 +11,65 @@ import (
 func TestHeap(t *testing.T) {
 	possibleValues1 := set.New(4)
 	possibleValues1.Add(3)
-	f1 := fieldToFill{
+	f1 := &fieldToFill{
 		x:              1,
 		y:              1,
 		possibleValues: possibleValues1.Copy(),
 	}
 	possibleValues1.Add(2)
-	f2 := fieldToFill{
+	f2 := &fieldToFill{
 		x:              2,
 		y:              2,
 		possibleValues: possibleValues1.Copy(),
 	}
 	possibleValues1.Add(1)
-	f3 := fieldToFill{
+	f3 := &fieldToFill{
 		x:              3,
 		y:              3,
 		possibleValues: possibleValues1.Copy(),
 	}
-	h := &fieldsToFillHeap{f1, f3}
+	h := &fieldsToFillHeap{}
+	h.Push(f1) // Push sets index, so the heap can be initialized with heap.Init
+	h.Push(f3)
 	heap.Init(h)
 	heap.Push(h, f2)
-	gotHeapValues := make([]fieldToFill, 0, 3)
-	expectedHeapValues := []fieldToFill{f1, f2, f3}
+	for i, f := range *h {
+		assert.Equal(t, i, f.index)
+	}
+	gotHeapValues := make([]*fieldToFill, 0, 3)
+	expectedHeapValues := []*fieldToFill{f1, f2, f3}
 	for h.Len() > 0 {
-		f := heap.Pop(h).(fieldToFill)
+		f := heap.Pop(h).(*fieldToFill)
+		assert.Equal(t, -1, f.index)
 		gotHeapValues = append(gotHeapValues, f)
 	}
 	assert.Equal(t, expectedHeapValues, gotHeapValues)
 }
+
+func TestHeapFix(t *testing.T) {
+	h := &fieldsToFillHeap{}
+	fields := make([]*fieldToFill, 0, 4)
+	for i := 1; i <= 4; i++ {
+		possibleValues := set.New(4)
+		for n := 1; n <= i; n++ {
+			possibleValues.Add(n)
+		}
+		f := &fieldToFill{x: i, y: i, possibleValues: possibleValues}
+		fields = append(fields, f)
+		heap.Push(h, f)
+	}
+	// field with the most possible values becomes the one with the least
+	fields[3].possibleValues.Clear()
+	heap.Fix(h, fields[3].index)
+	assert.Equal(t, fields[3], (*h)[0])
+	// field with the least possible values becomes the one with the most
+	fields[0].possibleValues.Fill()
+	heap.Fix(h, fields[0].index)
+	heap.Remove(h, fields[1].index)
+	assert.Equal(t, -1, fields[1].index)
+	gotHeapValues := make([]*fieldToFill, 0, 3)
+	for h.Len() > 0 {
+		gotHeapValues = append(gotHeapValues, heap.Pop(h).(*fieldToFill))
+	}
+	assert.Equal(t, []*fieldToFill{fields[3], fields[2], fields[0]}, gotHeapValues)
+}
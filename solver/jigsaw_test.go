@@ -0,0 +1,69 @@
+package solver_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tomaszmj/sudoku/board"
+	"github.com/tomaszmj/sudoku/solver"
+)
+
+func TestJigsaw(t *testing.T) {
+	solvers := map[string]solver.Solver{
+		"smartBacktrack": solver.NewSmartBarcktrack(),
+		"dlx":            solver.NewDLX(),
+		"logical":        solver.NewLogical(),
+		"sat":            solver.NewSAT(),
+	}
+	for name, s := range solvers {
+		t.Run(name, func(t *testing.T) {
+			s.Reset(jigsaw6x6)
+			solution := s.NextSolution()
+			require.NotNil(t, solution)
+			assert.Equal(t, jigsaw6x6Solution.String(), solution.String())
+			assert.True(t, solution.Equal(jigsaw6x6Solution))
+			assert.Nil(t, s.NextSolution())
+		})
+	}
+
+	t.Run("bruteforce", func(t *testing.T) {
+		empty, err := board.NewJigsaw([][]int{
+			{0, 0, 0, 1},
+			{2, 0, 1, 1},
+			{2, 2, 3, 1},
+			{2, 3, 3, 3},
+		})
+		require.NoError(t, err)
+		dlx := solver.NewDLX()
+		dlx.Reset(empty)
+		expected := dlx.NextSolution()
+		require.NotNil(t, expected)
+		require.NoError(t, expected.CheckRules())
+		puzzle := expected.Copy()
+		puzzle.Set(0, 0, 0)
+		puzzle.Set(2, 1, 0)
+		puzzle.Set(3, 3, 0)
+		s := solver.NewBruteforce()
+		s.Reset(puzzle)
+		solution := s.NextSolution()
+		require.NotNil(t, solution)
+		assert.True(t, solution.Equal(expected))
+		assert.Nil(t, s.NextSolution())
+	})
+
+	t.Run("count solutions", func(t *testing.T) {
+		assert.True(t, solver.IsUnique(jigsaw6x6))
+	})
+
+	t.Run("rectangular subgrid rules do not apply", func(t *testing.T) {
+		// in the first row of regular 3x2 board, 1 and 6 would be in one subgrid
+		require.NoError(t, jigsaw6x6Solution.CheckRules())
+		regular, err := board.New(3, 2)
+		require.NoError(t, err)
+		jigsaw6x6Solution.ForEach(func(x, y int, n uint16) {
+			regular.Set(x, y, n)
+		})
+		assert.Error(t, regular.CheckRules())
+	})
+}
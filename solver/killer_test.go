@@ -0,0 +1,87 @@
+package solver_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tomaszmj/sudoku/board"
+	"github.com/tomaszmj/sudoku/solver"
+)
+
+func TestKiller(t *testing.T) {
+	solvers := map[string]solver.Solver{
+		"smartBacktrack": solver.NewSmartBarcktrack(),
+		"sat":            solver.NewSAT(),
+	}
+	for name, s := range solvers {
+		t.Run(name, func(t *testing.T) {
+			s.Reset(killer6x6)
+			solution := s.NextSolution()
+			require.NotNil(t, solution)
+			assert.Equal(t, killer6x6Solution.String(), solution.String())
+			assert.NoError(t, solution.CheckRules())
+			assert.Equal(t, killer6x6.Cages(), solution.Cages())
+			assert.Nil(t, s.NextSolution())
+		})
+	}
+
+	t.Run("bruteforce", func(t *testing.T) {
+		puzzle := killer6x6.Copy()
+		killer6x6Solution.ForEach(func(x, y int, n uint16) {
+			puzzle.Set(x, y, n)
+		})
+		// bruteforce is too slow for the whole puzzle, so most of the numbers are given
+		puzzle.Set(0, 0, 0)
+		puzzle.Set(3, 0, 0)
+		puzzle.Set(0, 2, 0)
+		puzzle.Set(3, 3, 0)
+		s := solver.NewBruteforce()
+		s.Reset(puzzle)
+		solution := s.NextSolution()
+		require.NotNil(t, solution)
+		assert.Equal(t, killer6x6Solution.String(), solution.String())
+		assert.Nil(t, s.NextSolution())
+	})
+
+	t.Run("dlx", func(t *testing.T) {
+		// dlx skips solutions breaking cage rules, but it does not use cages for pruning,
+		// so it is too slow for the puzzle without given numbers
+		puzzle := killer6x6.Copy()
+		killer6x6Solution.ForEach(func(x, y int, n uint16) {
+			if y < 2 {
+				puzzle.Set(x, y, n)
+			}
+		})
+		s := solver.NewDLX()
+		s.Reset(puzzle)
+		solution := s.NextSolution()
+		require.NotNil(t, solution)
+		assert.Equal(t, killer6x6Solution.String(), solution.String())
+		assert.Nil(t, s.NextSolution())
+	})
+
+	t.Run("count solutions", func(t *testing.T) {
+		assert.True(t, solver.IsUnique(killer6x6))
+		empty, err := board.New(3, 2)
+		require.NoError(t, err)
+		assert.False(t, solver.IsUnique(empty))
+	})
+
+	t.Run("cage sums prune candidates", func(t *testing.T) {
+		// without pruning the solver would have to guess almost every number
+		s := solver.NewSmartBarcktrack()
+		s.Reset(killer6x6)
+		require.NotNil(t, s.NextSolution())
+		assert.Less(t, s.(solver.StatsReporter).Stats().Guesses, 10)
+	})
+
+	t.Run("broken cage sum", func(t *testing.T) {
+		puzzle := killer6x6.Copy()
+		puzzle.Set(0, 0, 6) // cage 8: (0, 0) (1, 0) would need 2 in (1, 0), but 2 is in that column
+		puzzle.Set(1, 2, 2)
+		s := solver.NewSmartBarcktrack()
+		s.Reset(puzzle)
+		assert.Nil(t, s.NextSolution())
+	})
+}
@@ -0,0 +1,466 @@
+package solver
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/tomaszmj/sudoku/board"
+	"github.com/tomaszmj/sudoku/set"
+)
+
+// Technique is a kind of reasoning used by human sudoku players
+// to place a number or to eliminate some candidates.
+type Technique int
+
+// Techniques are ordered from the easiest to the most difficult one.
+const (
+	NakedSingle Technique = iota
+	HiddenSingle
+	PointingPair
+	BoxLineReduction
+	NakedPair
+	HiddenPair
+	NakedTriple
+	HiddenTriple
+)
+
+func (t Technique) String() string {
+	switch t {
+	case NakedSingle:
+		return "naked single"
+	case HiddenSingle:
+		return "hidden single"
+	case PointingPair:
+		return "pointing pair"
+	case BoxLineReduction:
+		return "box/line reduction"
+	case NakedPair:
+		return "naked pair"
+	case HiddenPair:
+		return "hidden pair"
+	case NakedTriple:
+		return "naked triple"
+	case HiddenTriple:
+		return "hidden triple"
+	}
+	return fmt.Sprintf("Technique(%d)", int(t))
+}
+
+// Candidate is number n that can be (or could have been) placed on field x, y.
+type Candidate struct {
+	X, Y int
+	N    uint16
+}
+
+// Step is a single deduction made by Logical solver.
+// Fields are the fields that form the pattern recognized by Technique.
+// Singles place exactly one number (Placed), all other techniques only
+// remove candidates (Eliminated).
+type Step struct {
+	Technique  Technique
+	Fields     []board.Field
+	Placed     []Candidate
+	Eliminated []Candidate
+	Reason     string
+}
+
+// Logical solver never guesses - it uses only techniques that human players
+// use (see Technique) and records each deduction as a Step, so that it can be
+// explained why a number was placed on a field.
+// If the board cannot be solved with the known techniques, NextSolution
+// returns nil, but Steps still contain deductions made until getting stuck.
+// Board solved with logic has exactly one solution, so NextSolution
+// never returns more than one.
+type Logical struct {
+	board        *board.Board
+	units        []board.Unit
+	unitsOfField [][]int // indexes of units for each field (by offset y*size+x)
+	candidates   *board.Candidates
+	steps        []Step
+	solvable     bool
+}
+
+func NewLogical() *Logical {
+	return &Logical{}
+}
+
+func (l *Logical) Reset(b *board.Board) {
+	l.steps = nil
+	if err := b.CheckRules(); err != nil {
+		l.solvable = false
+		return
+	}
+	l.solvable = true
+	l.board = b.Copy()
+	l.units = b.Units()
+	size := b.Size()
+	l.unitsOfField = make([][]int, size*size)
+	for i, unit := range l.units {
+		for _, f := range unit.Fields {
+			offset := l.offset(f.X, f.Y)
+			l.unitsOfField[offset] = append(l.unitsOfField[offset], i)
+		}
+	}
+	l.candidates = board.NewCandidates(l.board)
+}
+
+// Steps returns deductions made so far by NextSolution.
+func (l *Logical) Steps() []Step {
+	return l.steps
+}
+
+// Candidates returns candidates (pencil marks) left after deductions made so far by NextSolution.
+// The returned value must not be modified - it is updated by subsequent NextSolution calls.
+func (l *Logical) Candidates() *board.Candidates {
+	return l.candidates
+}
+
+func (l *Logical) NextSolution() *board.Board {
+	if !l.solvable {
+		return nil
+	}
+	l.solvable = false // there is either no solution or only one solution, so next call will return nil
+	techniques := []func() (bool, error){
+		l.findNakedSingle,
+		l.findHiddenSingle,
+		l.findIntersection,
+		func() (bool, error) { return l.findNakedSubset(2, NakedPair) },
+		func() (bool, error) { return l.findHiddenSubset(2, HiddenPair) },
+		func() (bool, error) { return l.findNakedSubset(3, NakedTriple) },
+		func() (bool, error) { return l.findHiddenSubset(3, HiddenTriple) },
+	}
+	for !l.solved() {
+		progress := false
+		// always start from the easiest technique, so that the steps are as simple as possible
+		for _, technique := range techniques {
+			found, err := technique()
+			if err != nil {
+				return nil
+			}
+			if found {
+				progress = true
+				break
+			}
+		}
+		if !progress {
+			return nil
+		}
+	}
+	// techniques use only units, so cages and constraints must be checked at the end
+	if l.board.CheckRules() != nil {
+		return nil
+	}
+	return l.board.Copy()
+}
+
+func (l *Logical) offset(x, y int) int {
+	return y*l.board.Size() + x
+}
+
+func (l *Logical) solved() bool {
+	solved := true
+	l.board.ForEach(func(x, y int, n uint16) {
+		solved = solved && n != 0
+	})
+	return solved
+}
+
+func (l *Logical) place(step Step) {
+	c := step.Placed[0]
+	l.candidates.Place(l.board, c.X, c.Y, c.N)
+	l.steps = append(l.steps, step)
+}
+
+// eliminate removes given candidates and records step. It does nothing and
+// returns false if all candidates have already been eliminated.
+func (l *Logical) eliminate(step Step, candidates []Candidate) bool {
+	for _, c := range candidates {
+		if l.candidates.Eliminate(c.X, c.Y, c.N) {
+			step.Eliminated = append(step.Eliminated, c)
+		}
+	}
+	if len(step.Eliminated) == 0 {
+		return false
+	}
+	l.steps = append(l.steps, step)
+	return true
+}
+
+func (l *Logical) findNakedSingle() (bool, error) {
+	for offset := 0; offset < l.board.Size()*l.board.Size(); offset++ {
+		x, y := offset%l.board.Size(), offset/l.board.Size()
+		candidates := l.candidates.Get(x, y)
+		if candidates.Len() == 0 && l.board.Get(x, y) == 0 {
+			return false, fmt.Errorf("there are no candidates left for field %d, %d", x, y)
+		}
+		if candidates.Len() != 1 {
+			continue
+		}
+		n := uint16(candidates.ForEach(func(int) bool { return true }))
+		l.place(Step{
+			Technique: NakedSingle,
+			Fields:    []board.Field{{X: x, Y: y}},
+			Placed:    []Candidate{{x, y, n}},
+			Reason:    fmt.Sprintf("%d is the only candidate left for field %d, %d", n, x, y),
+		})
+		return true, nil
+	}
+	return false, nil
+}
+
+func (l *Logical) findHiddenSingle() (bool, error) {
+	for _, unit := range l.units {
+		for n := 1; n <= l.board.Size(); n++ {
+			fields, placed := l.fieldsWithCandidate(unit, n)
+			if placed {
+				continue
+			}
+			if len(fields) == 0 {
+				return false, fmt.Errorf("number %d cannot be placed anywhere in %s %d", n, unit.Kind, unit.Index)
+			}
+			if len(fields) == 1 {
+				f := fields[0]
+				l.place(Step{
+					Technique: HiddenSingle,
+					Fields:    fields,
+					Placed:    []Candidate{{f.X, f.Y, uint16(n)}},
+					Reason:    fmt.Sprintf("field %d, %d is the only place for %d in %s %d", f.X, f.Y, n, unit.Kind, unit.Index),
+				})
+				return true, nil
+			}
+		}
+	}
+	return false, nil
+}
+
+// findIntersection looks for pointing pairs (number in a subgrid can be placed only
+// in one row / column, so it can be removed from the rest of that row / column)
+// and box/line reductions (number in a row / column can be placed only in one subgrid,
+// so it can be removed from the rest of that subgrid).
+func (l *Logical) findIntersection() (bool, error) {
+	for _, unit := range l.units {
+		for n := 1; n <= l.board.Size(); n++ {
+			fields, placed := l.fieldsWithCandidate(unit, n)
+			if placed || len(fields) < 2 {
+				continue
+			}
+			for _, otherIndex := range l.unitsOfField[l.offset(fields[0].X, fields[0].Y)] {
+				other := l.units[otherIndex]
+				var technique Technique
+				switch {
+				case unit.Kind == board.Subgrid && other.Kind != board.Subgrid:
+					technique = PointingPair
+				case unit.Kind != board.Subgrid && other.Kind == board.Subgrid:
+					technique = BoxLineReduction
+				default:
+					continue
+				}
+				if !l.allInUnit(fields, otherIndex) {
+					continue
+				}
+				var toEliminate []Candidate
+				for _, f := range other.Fields {
+					if !containsField(unit.Fields, f) {
+						toEliminate = append(toEliminate, Candidate{f.X, f.Y, uint16(n)})
+					}
+				}
+				step := Step{
+					Technique: technique,
+					Fields:    fields,
+					Reason: fmt.Sprintf("%d in %s %d can only be placed in fields %s, which are all in %s %d",
+						n, unit.Kind, unit.Index, fieldsString(fields), other.Kind, other.Index),
+				}
+				if l.eliminate(step, toEliminate) {
+					return true, nil
+				}
+			}
+		}
+	}
+	return false, nil
+}
+
+// findNakedSubset looks for size fields in one unit which together have only size candidates.
+// These candidates can be removed from other fields in the unit.
+func (l *Logical) findNakedSubset(size int, technique Technique) (bool, error) {
+	for _, unit := range l.units {
+		var fields []board.Field
+		for _, f := range unit.Fields {
+			if count := l.candidates.Get(f.X, f.Y).Len(); count >= 2 && count <= size {
+				fields = append(fields, f)
+			}
+		}
+		found := false
+		forEachCombination(len(fields), size, func(indexes []int) bool {
+			subset := make([]board.Field, 0, size)
+			sets := make([]*set.Set, 0, size)
+			for _, i := range indexes {
+				subset = append(subset, fields[i])
+				sets = append(sets, l.candidates.Get(fields[i].X, fields[i].Y))
+			}
+			union := set.Union(sets...)
+			if union.Len() != size {
+				return false
+			}
+			var toEliminate []Candidate
+			for _, f := range unit.Fields {
+				if containsField(subset, f) {
+					continue
+				}
+				union.ForEach(func(n int) bool {
+					toEliminate = append(toEliminate, Candidate{f.X, f.Y, uint16(n)})
+					return false
+				})
+			}
+			step := Step{
+				Technique: technique,
+				Fields:    subset,
+				Reason: fmt.Sprintf("fields %s in %s %d can only contain numbers %s",
+					fieldsString(subset), unit.Kind, unit.Index, numbersString(union)),
+			}
+			found = l.eliminate(step, toEliminate)
+			return found
+		})
+		if found {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// findHiddenSubset looks for size numbers in one unit which can be placed only in size fields.
+// All other candidates can be removed from these fields.
+func (l *Logical) findHiddenSubset(size int, technique Technique) (bool, error) {
+	for _, unit := range l.units {
+		var numbers []int
+		var numbersFields [][]board.Field
+		for n := 1; n <= l.board.Size(); n++ {
+			fields, placed := l.fieldsWithCandidate(unit, n)
+			if !placed && len(fields) >= 2 && len(fields) <= size {
+				numbers = append(numbers, n)
+				numbersFields = append(numbersFields, fields)
+			}
+		}
+		found := false
+		forEachCombination(len(numbers), size, func(indexes []int) bool {
+			var subset []board.Field
+			subsetNumbers := set.New(l.board.Size())
+			for _, i := range indexes {
+				subsetNumbers.Add(numbers[i])
+				for _, f := range numbersFields[i] {
+					if !containsField(subset, f) {
+						subset = append(subset, f)
+					}
+				}
+			}
+			if len(subset) != size {
+				return false
+			}
+			var toEliminate []Candidate
+			for _, f := range subset {
+				l.candidates.Get(f.X, f.Y).ForEach(func(n int) bool {
+					if !subsetNumbers.Get(n) {
+						toEliminate = append(toEliminate, Candidate{f.X, f.Y, uint16(n)})
+					}
+					return false
+				})
+			}
+			step := Step{
+				Technique: technique,
+				Fields:    subset,
+				Reason: fmt.Sprintf("numbers %s in %s %d can only be placed in fields %s",
+					numbersString(subsetNumbers), unit.Kind, unit.Index, fieldsString(subset)),
+			}
+			found = l.eliminate(step, toEliminate)
+			return found
+		})
+		if found {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// fieldsWithCandidate returns fields from unit, which have n as a candidate.
+// If n is already placed in the unit, it returns true as the second value.
+func (l *Logical) fieldsWithCandidate(unit board.Unit, n int) ([]board.Field, bool) {
+	var fields []board.Field
+	for _, f := range unit.Fields {
+		if l.board.Get(f.X, f.Y) == uint16(n) {
+			return nil, true
+		}
+		if l.candidates.Get(f.X, f.Y).Get(n) {
+			fields = append(fields, f)
+		}
+	}
+	return fields, false
+}
+
+func (l *Logical) allInUnit(fields []board.Field, unitIndex int) bool {
+	for _, f := range fields {
+		found := false
+		for _, i := range l.unitsOfField[l.offset(f.X, f.Y)] {
+			if i == unitIndex {
+				found = true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func containsField(fields []board.Field, field board.Field) bool {
+	for _, f := range fields {
+		if f == field {
+			return true
+		}
+	}
+	return false
+}
+
+// forEachCombination executes operation for each k-element combination of indexes 0..n-1
+// until it is called for all combinations or until operation returns true.
+func forEachCombination(n, k int, operation func(indexes []int) bool) {
+	if k > n {
+		return
+	}
+	indexes := make([]int, k)
+	for i := range indexes {
+		indexes[i] = i
+	}
+	for {
+		if operation(indexes) {
+			return
+		}
+		i := k - 1
+		for i >= 0 && indexes[i] == n-k+i {
+			i--
+		}
+		if i < 0 {
+			return
+		}
+		indexes[i]++
+		for j := i + 1; j < k; j++ {
+			indexes[j] = indexes[j-1] + 1
+		}
+	}
+}
+
+func fieldsString(fields []board.Field) string {
+	parts := make([]string, 0, len(fields))
+	for _, f := range fields {
+		parts = append(parts, fmt.Sprintf("(%d, %d)", f.X, f.Y))
+	}
+	return strings.Join(parts, ", ")
+}
+
+func numbersString(s *set.Set) string {
+	parts := make([]string, 0, s.Len())
+	s.ForEach(func(n int) bool {
+		parts = append(parts, fmt.Sprint(n))
+		return false
+	})
+	return strings.Join(parts, ", ")
+}
@@ -0,0 +1,97 @@
+package solver_test
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tomaszmj/sudoku/board"
+	"github.com/tomaszmj/sudoku/solver"
+)
+
+func TestLogical(t *testing.T) {
+	logical := solver.NewLogical()
+
+	t.Run("puzzle already solved", func(t *testing.T) {
+		logical.Reset(solvedBoard)
+		solution := logical.NextSolution()
+		require.NotNil(t, solution)
+		assert.Equal(t, solvedBoard.String(), solution.String())
+		assert.Empty(t, logical.Steps())
+	})
+
+	t.Run("unsolveable puzzle", func(t *testing.T) {
+		logical.Reset(unsolveableBoard)
+		require.Nil(t, logical.NextSolution())
+	})
+
+	t.Run("invalid puzzle", func(t *testing.T) {
+		logical.Reset(invalidBoard)
+		require.Nil(t, logical.NextSolution())
+	})
+
+	t.Run("puzzle with many solutions cannot be solved without guessing", func(t *testing.T) {
+		logical.Reset(boardWithManySoltions)
+		require.Nil(t, logical.NextSolution())
+		assert.Empty(t, logical.Steps())
+	})
+
+	for name, b := range map[string]*board.Board{
+		"4x4":      boardToSolve,
+		"6x6":      board6x6,
+		"9x9 easy": board9x9Easy,
+		"12x12":    board12x12,
+	} {
+		t.Run("solve "+name, func(t *testing.T) {
+			expected := solveWithSmartBacktrack(t, b)
+			logical.Reset(b)
+			solution := logical.NextSolution()
+			require.NotNil(t, solution)
+			assert.Equal(t, expected.String(), solution.String())
+			assertStepsMatchSolution(t, logical.Steps(), expected)
+			require.Nil(t, logical.NextSolution())
+		})
+	}
+
+	for name, b := range map[string]*board.Board{
+		"9x9 difficult":      difficultBoard,
+		"9x9 very difficult": board9x9Difficult,
+	} {
+		t.Run("steps are recorded even if "+name+" puzzle cannot be solved with logic", func(t *testing.T) {
+			expected := solveWithSmartBacktrack(t, b)
+			logical.Reset(b)
+			require.Nil(t, logical.NextSolution())
+			assert.NotEmpty(t, logical.Steps())
+			assertStepsMatchSolution(t, logical.Steps(), expected)
+			candidates := logical.Candidates()
+			b.ForEach(func(x, y int, n uint16) {
+				if n == 0 && candidates.Get(x, y).Len() > 0 {
+					assert.True(t, candidates.Has(x, y, expected.Get(x, y)), "correct number is not a candidate of %d, %d", x, y)
+				}
+			})
+		})
+	}
+}
+
+func solveWithSmartBacktrack(t *testing.T, b *board.Board) *board.Board {
+	s := solver.NewSmartBarcktrack()
+	s.Reset(b)
+	solution := s.NextSolution()
+	require.NotNil(t, solution)
+	return solution
+}
+
+// assertStepsMatchSolution checks that no step placed wrong number or eliminated the correct one.
+func assertStepsMatchSolution(t *testing.T, steps []solver.Step, solution *board.Board) {
+	for _, step := range steps {
+		assert.NotEmpty(t, step.Reason)
+		assert.NotEmpty(t, step.Fields)
+		assert.NotEqual(t, len(step.Placed) == 0, len(step.Eliminated) == 0, "step must either place or eliminate: %+v", step)
+		for _, c := range step.Placed {
+			assert.Equal(t, solution.Get(c.X, c.Y), c.N, "wrong number placed in step %+v", step)
+		}
+		for _, c := range step.Eliminated {
+			assert.NotEqual(t, solution.Get(c.X, c.Y), c.N, "correct number eliminated in step %+v", step)
+		}
+	}
+}
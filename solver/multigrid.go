@@ -0,0 +1,74 @@
+package solver
+
+import (
+	"context"
+
+	"github.com/tomaszmj/sudoku/board"
+)
+
+// MultiGrid solves multi-grid puzzles (see board.MultiGrid), such as Samurai Sudoku.
+// Grids are not solved one by one - the whole puzzle is transformed into one exact
+// cover problem (see dlx), in which each shared field is a single field belonging to
+// units of all grids containing it. Thanks to that, constraints from one grid
+// are spread to the others through the shared fields.
+type MultiGrid struct {
+	grid *board.MultiGrid
+	dlx  dlx
+}
+
+func NewMultiGrid() *MultiGrid {
+	return &MultiGrid{}
+}
+
+func (s *MultiGrid) Reset(m *board.MultiGrid) {
+	s.dlx.err = m.CheckRules()
+	if s.dlx.err != nil {
+		s.dlx.solvable = false
+		return
+	}
+	s.grid = m.Copy()
+	var fields []board.Field
+	var given []uint16
+	indexes := make(map[board.Field]int)
+	m.ForEach(func(x, y int, n uint16) {
+		indexes[board.Field{X: x, Y: y}] = len(fields)
+		fields = append(fields, board.Field{X: x, Y: y})
+		given = append(given, n)
+	})
+	var units [][]int
+	for _, unit := range m.Units() {
+		unitIndexes := make([]int, 0, len(unit.Fields))
+		for _, f := range unit.Fields {
+			unitIndexes = append(unitIndexes, indexes[f])
+		}
+		units = append(units, unitIndexes)
+	}
+	s.dlx.init(m.GridSize(), fields, given, units)
+}
+
+func (s *MultiGrid) NextSolution() *board.MultiGrid {
+	solution, _ := s.NextSolutionContext(context.Background())
+	return solution
+}
+
+// NextSolutionContext works just like ContextSolver.NextSolutionContext, but for multi-grid puzzles.
+func (s *MultiGrid) NextSolutionContext(ctx context.Context) (*board.MultiGrid, error) {
+	found, err := s.dlx.searchContext(ctx)
+	if err != nil || !found {
+		return nil, err
+	}
+	solution := s.grid.Copy()
+	for _, node := range s.dlx.stack {
+		c := s.dlx.candidates[node]
+		solution.Set(c.x, c.y, c.n)
+	}
+	return solution, nil
+}
+
+func (s *MultiGrid) Stats() Stats {
+	return s.dlx.stats
+}
+
+func (s *MultiGrid) SetHook(hook Hook) {
+	s.dlx.hook = hook
+}
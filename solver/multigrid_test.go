@@ -0,0 +1,85 @@
+package solver_test
+
+import (
+	"context"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tomaszmj/sudoku/board"
+	"github.com/tomaszmj/sudoku/solver"
+)
+
+func mustReadMultiGrid(t *testing.T, path string) *board.MultiGrid {
+	file, err := os.Open(path)
+	require.NoError(t, err)
+	defer file.Close()
+	m, err := board.NewMultiGridFromSerializedFormat(file)
+	require.NoError(t, err)
+	return m
+}
+
+func TestMultiGrid(t *testing.T) {
+	s := solver.NewMultiGrid()
+
+	t.Run("samurai", func(t *testing.T) {
+		puzzle := mustReadMultiGrid(t, "../cmd/boards/samurai.txt")
+		expected := mustReadMultiGrid(t, "../cmd/boards/samurai_solution.txt")
+		s.Reset(puzzle)
+		solution := s.NextSolution()
+		require.NotNil(t, solution)
+		assert.NoError(t, solution.CheckRules())
+		assert.Equal(t, expected.String(), solution.String())
+		assert.True(t, solution.Equal(expected))
+		assert.Nil(t, s.NextSolution())
+		assert.Equal(t, 1, solver.CountMultiGridSolutions(puzzle, 2))
+	})
+
+	t.Run("grids are not solved separately", func(t *testing.T) {
+		puzzle := mustReadMultiGrid(t, "../cmd/boards/samurai.txt")
+		for i := 0; i < puzzle.GridsCount(); i++ {
+			grid, _ := puzzle.Grid(i)
+			assert.False(t, solver.IsUnique(grid))
+		}
+	})
+
+	t.Run("enumerate all solutions", func(t *testing.T) {
+		m, err := board.NewMultiGrid(1, 2, []board.Field{{X: 0, Y: 0}, {X: 1, Y: 0}})
+		require.NoError(t, err)
+		s.Reset(m)
+		solutions := make(map[string]bool)
+		for solution := s.NextSolution(); solution != nil; solution = s.NextSolution() {
+			require.NoError(t, solution.CheckRules())
+			solutions[solution.String()] = true
+		}
+		// 2 possible first grids, the second one shares its left column
+		assert.Len(t, solutions, 2)
+		assert.Equal(t, 2, solver.CountMultiGridSolutions(m, 0))
+		assert.Equal(t, 1, solver.CountMultiGridSolutions(m, 1))
+	})
+
+	t.Run("invalid puzzle", func(t *testing.T) {
+		m, err := board.NewMultiGrid(2, 2, []board.Field{{X: 0, Y: 0}, {X: 2, Y: 2}})
+		require.NoError(t, err)
+		m.Set(3, 3, 1)
+		m.Set(5, 3, 1)
+		assert.Equal(t, 0, solver.CountMultiGridSolutions(m, 0))
+		s.Reset(m)
+		solution, err := s.NextSolutionContext(context.Background())
+		assert.Nil(t, solution)
+		var violations board.Violations
+		assert.ErrorAs(t, err, &violations)
+	})
+
+	t.Run("cancelled context", func(t *testing.T) {
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		s.Reset(board.NewSamurai())
+		_, err := s.NextSolutionContext(ctx)
+		assert.ErrorIs(t, err, context.Canceled)
+		solution, err := s.NextSolutionContext(context.Background())
+		require.NoError(t, err)
+		assert.NoError(t, solution.CheckRules())
+	})
+}
@@ -0,0 +1,221 @@
+package solver
+
+import (
+	"context"
+	"runtime"
+	"sync"
+
+	"github.com/tomaszmj/sudoku/board"
+)
+
+// subtreesPerWorker is the minimum number of subtrees per worker, to which search tree is split.
+// Subtrees differ in size a lot, so with only one subtree per worker most workers would be idle soon.
+const subtreesPerWorker = 4
+
+// maxSplitDepth is the maximum number of branching points (levels of the search tree) at which
+// search tree is split, even if there are not enough subtrees yet.
+const maxSplitDepth = 8
+
+// Parallel solver splits search tree at its first branching points (see smartBacktrack.branchingPoint)
+// into subtrees, each of them is a copy of the board with numbers chosen at these branching points.
+// Subtrees are searched by worker goroutines, each of them with its own smartBacktrack,
+// and solutions found are merged through a channel. NextSolution returns each solution once,
+// just like other solvers, but solutions are not ordered in the same way as in smartBacktrack
+// and the order may differ between runs.
+//
+// Workers are started by the first call of NextSolution after Reset. Each of them looks for
+// at most one solution ahead (it waits until the solution it has found is taken by NextSolution).
+// Workers are stopped when all solutions have been found, by Reset, or by Close - it has to be
+// called if the search is abandoned before NextSolution returns nil.
+type Parallel struct {
+	workers  int
+	subtrees []*board.Board
+	err      error // validation error of the initial board
+	started  bool
+	cancel   context.CancelFunc
+	results  chan *board.Board
+	mu       sync.Mutex // guards stats
+	stats    Stats
+	hookMu   sync.Mutex // serializes calls of hook (separate from mu, so that hook can call Stats)
+	hook     Hook
+}
+
+// NewParallel returns Parallel solver with given number of workers.
+// If workers is not positive, runtime.NumCPU() workers are used.
+func NewParallel(workers int) *Parallel {
+	if workers <= 0 {
+		workers = runtime.NumCPU()
+	}
+	return &Parallel{workers: workers}
+}
+
+func (p *Parallel) Reset(b *board.Board) {
+	p.Close()
+	p.stats = Stats{}
+	p.started = false
+	p.subtrees = nil
+	p.err = b.CheckRules()
+	if p.err != nil {
+		return
+	}
+	p.subtrees = p.split(b)
+}
+
+// split returns subtrees of the search tree of the board. Levels of the tree are split one by one,
+// until there are at least subtreesPerWorker subtrees per worker (or maxSplitDepth is reached).
+// Subtrees without solution are skipped, solved boards are kept as they are (they are their only solutions).
+func (p *Parallel) split(b *board.Board) []*board.Board {
+	s := &smartBacktrack{}
+	subtrees := []*board.Board{b.Copy()}
+	for depth := 0; depth < maxSplitDepth && len(subtrees) < p.workers*subtreesPerWorker; depth++ {
+		var next []*board.Board
+		branched := false
+		for _, subtree := range subtrees {
+			s.Reset(subtree)
+			if s.err != nil {
+				continue // numbers chosen at previous levels break some constraint
+			}
+			f, solved := s.branchingPoint()
+			if f == nil {
+				if solved {
+					next = append(next, s.board.Copy())
+				}
+				continue
+			}
+			branched = true
+			f.possibleValues.ForEach(func(n int) bool {
+				child := s.board.Copy()
+				child.Set(f.x, f.y, uint16(n))
+				next = append(next, child)
+				return false
+			})
+		}
+		subtrees = next
+		if !branched {
+			break
+		}
+	}
+	return subtrees
+}
+
+// start starts workers, which search subtrees and send solutions to results
+// (it is closed when all workers are done).
+func (p *Parallel) start() {
+	p.started = true
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	results := make(chan *board.Board)
+	p.results = results
+	subtrees := make(chan *board.Board, len(p.subtrees))
+	for _, subtree := range p.subtrees {
+		subtrees <- subtree
+	}
+	close(subtrees)
+	var wg sync.WaitGroup
+	for i := 0; i < p.workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			p.work(ctx, subtrees, results)
+		}()
+	}
+	go func() {
+		wg.Wait()
+		close(results)
+	}()
+}
+
+// work searches all solutions of subtrees taken from the channel, until ctx is done.
+func (p *Parallel) work(ctx context.Context, subtrees <-chan *board.Board, results chan<- *board.Board) {
+	s := &smartBacktrack{}
+	if p.hook != nil {
+		s.SetHook(func(e Event) {
+			p.hookMu.Lock()
+			defer p.hookMu.Unlock()
+			p.hook(e)
+		})
+	}
+	for subtree := range subtrees {
+		s.Reset(subtree)
+		for s.solvable {
+			var solution *board.Board
+			err := s.search(ctx, func(b *board.Board) {
+				solution = b.Copy()
+			})
+			p.addStats(s.stats)
+			s.stats = Stats{}
+			if err != nil {
+				return
+			}
+			if solution == nil {
+				continue
+			}
+			select {
+			case results <- solution:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}
+}
+
+func (p *Parallel) addStats(stats Stats) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.stats.add(stats)
+}
+
+func (p *Parallel) NextSolution() *board.Board {
+	solution, _ := p.NextSolutionContext(context.Background())
+	return solution
+}
+
+// NextSolutionContext can be called again after it has been interrupted - workers
+// keep searching in the meantime (until they find solutions that are not taken yet).
+func (p *Parallel) NextSolutionContext(ctx context.Context) (*board.Board, error) {
+	if p.err != nil {
+		return nil, p.err
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if !p.started {
+		p.start()
+	}
+	select {
+	case solution := <-p.results:
+		return solution, nil // solution is nil if results are closed
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// Stats returns statistics of all workers. Search performed by Reset to split
+// the search tree is not included, so MaxDepth is the depth reached in subtrees.
+// SolutionTimes are times spent by workers on searching each solution (they are not
+// in the order in which solutions are returned by NextSolution).
+func (p *Parallel) Stats() Stats {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.stats
+}
+
+// SetHook sets hook called for events of all workers. Calls of the hook are serialized,
+// but events of different subtrees are interleaved. The hook may call Stats. SetHook must not
+// be called while workers are running.
+func (p *Parallel) SetHook(hook Hook) {
+	p.hook = hook
+}
+
+// Close stops workers and waits until they are done. After Close NextSolution returns nil
+// until the next Reset.
+func (p *Parallel) Close() {
+	if p.cancel != nil {
+		p.cancel()
+		p.cancel = nil
+		for range p.results {
+			// results are closed when all workers are done
+		}
+	}
+	p.subtrees = nil
+}
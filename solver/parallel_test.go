@@ -0,0 +1,83 @@
+package solver_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"github.com/tomaszmj/sudoku/board"
+	"github.com/tomaszmj/sudoku/solver"
+)
+
+func TestParallel(t *testing.T) {
+	for _, workers := range []int{1, 4, 0} {
+		s := solver.NewParallel(workers)
+		genericTestSolver(t, s)
+		testDifficultPuzzle(t, s)
+		s.Close()
+	}
+
+	t.Run("the same solutions as smartBacktrack", func(t *testing.T) {
+		p := solver.NewParallel(4)
+		defer p.Close()
+		smartBacktrack := solver.NewSmartBarcktrack()
+		empty, err := board.New(2, 2)
+		require.NoError(t, err)
+		for _, b := range []*board.Board{empty, boardWithManySoltions, jigsaw6x6, diagonal6x6, killer6x6, board12x12} {
+			expected := make(map[string]bool)
+			smartBacktrack.Reset(b)
+			for solution := smartBacktrack.NextSolution(); solution != nil; solution = smartBacktrack.NextSolution() {
+				expected[solution.String()] = true
+			}
+			p.Reset(b)
+			solutions := make(map[string]bool)
+			for solution := p.NextSolution(); solution != nil; solution = p.NextSolution() {
+				assert.False(t, solutions[solution.String()], "solution found twice:\n%s", solution)
+				solutions[solution.String()] = true
+			}
+			assert.Equal(t, expected, solutions)
+		}
+	})
+
+	t.Run("16x16 puzzle", func(t *testing.T) {
+		p := solver.NewParallel(4)
+		defer p.Close()
+		p.Reset(board16x16)
+		solution := p.NextSolution()
+		require.NotNil(t, solution)
+		assert.True(t, solution.Equal(board16x16Solution))
+		assert.Nil(t, p.NextSolution())
+		assert.Greater(t, p.Stats().Guesses, 0)
+	})
+
+	t.Run("reset before all solutions are found", func(t *testing.T) {
+		p := solver.NewParallel(4)
+		defer p.Close()
+		empty, err := board.New(4, 4)
+		require.NoError(t, err)
+		p.Reset(empty)
+		for i := 0; i < 10; i++ {
+			require.NotNil(t, p.NextSolution())
+		}
+		p.Reset(boardToSolve)
+		solution, err := p.NextSolutionContext(context.Background())
+		require.NoError(t, err)
+		assert.Equal(t, solvedBoard.String(), solution.String())
+		assert.Nil(t, p.NextSolution())
+	})
+
+	t.Run("closed solver", func(t *testing.T) {
+		p := solver.NewParallel(2)
+		p.Reset(boardWithManySoltions)
+		require.NotNil(t, p.NextSolution())
+		p.Close()
+		assert.Nil(t, p.NextSolution())
+	})
+}
+
+func BenchmarkParallel(b *testing.B) {
+	p := solver.NewParallel(0)
+	defer p.Close()
+	benchmarkSolver(b, p)
+}
@@ -0,0 +1,112 @@
+package solver
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/tomaszmj/sudoku/board"
+	"github.com/tomaszmj/sudoku/sat"
+)
+
+// satSolver transforms sudoku into SAT problem (see sat.Encode) and solves it
+// with sat.Solver. After each solution is found, clause excluding it is added,
+// so that the next call of NextSolution finds another one.
+// Constraints which cannot be encoded exactly (see sat.Encoding) are checked
+// for each model found - models which break them are excluded as well.
+type satSolver struct {
+	encoding    *sat.Encoding
+	solver      *sat.Solver
+	emptyFields []board.Field // fields empty on the initial board (only they are excluded)
+	solvable    bool
+	elapsed     time.Duration // time spent on searching since the last solution was found
+	solutions   []time.Duration
+	err         error // validation or encoding error of the initial board
+}
+
+// NewSAT returns Solver which uses built-in SAT solver.
+func NewSAT() Solver {
+	return &satSolver{}
+}
+
+func (s *satSolver) Reset(b *board.Board) {
+	s.err = b.CheckRules()
+	if s.err != nil {
+		s.solvable = false
+		return
+	}
+	s.encoding, s.err = sat.Encode(b)
+	if s.err != nil {
+		s.solvable = false
+		return
+	}
+	s.solvable = true
+	s.elapsed = 0
+	s.solutions = nil
+	s.solver = sat.NewSolver(s.encoding.CNF)
+	s.emptyFields = nil
+	b.ForEach(func(x, y int, n uint16) {
+		if n == 0 {
+			s.emptyFields = append(s.emptyFields, board.Field{X: x, Y: y})
+		}
+	})
+}
+
+func (s *satSolver) NextSolution() *board.Board {
+	solution, _ := s.NextSolutionContext(context.Background())
+	return solution
+}
+
+// NextSolutionContext can be called again after the search has been interrupted -
+// it is resumed from the place where it was stopped.
+func (s *satSolver) NextSolutionContext(ctx context.Context) (*board.Board, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	start := time.Now()
+	defer func() {
+		s.elapsed += time.Since(start)
+	}()
+	for s.solvable {
+		found, err := s.solver.Solve(ctx)
+		if err != nil {
+			return nil, err
+		}
+		if !found {
+			s.solvable = false
+			break
+		}
+		solution, err := s.encoding.Decode(s.solver.Model())
+		if err != nil {
+			panic(fmt.Sprintf("invalid model of sudoku encoding: %s", err))
+		}
+		if len(s.emptyFields) == 0 {
+			s.solvable = false // there is nothing to exclude, so the board is the only solution
+		} else if err := s.solver.AddClause(s.encoding.Exclude(solution, s.emptyFields)); err != nil {
+			panic(fmt.Sprintf("invalid clause excluding solution: %s", err))
+		}
+		if solution.CheckRules() == nil {
+			// only time spent after finding the solution is counted for the next one (see smartBacktrack.search)
+			s.solutions = append(s.solutions, s.elapsed+time.Since(start))
+			s.elapsed = -time.Since(start)
+			return solution, nil
+		}
+	}
+	return nil, nil
+}
+
+// Stats reports decisions of SAT solver as Guesses and Nodes, its conflicts as Backtracks
+// and literals assigned by unit propagation as Propagations. MaxDepth is not reported.
+func (s *satSolver) Stats() Stats {
+	if s.solver == nil {
+		return Stats{}
+	}
+	stats := s.solver.Stats()
+	return Stats{
+		Guesses:       stats.Decisions,
+		Backtracks:    stats.Conflicts,
+		Nodes:         stats.Decisions,
+		Propagations:  stats.Propagations,
+		SolutionTimes: s.solutions,
+	}
+}
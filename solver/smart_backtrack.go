@@ -2,7 +2,9 @@ package solver
 
 import (
 	"container/heap"
+	"context"
 	"fmt"
+	"time"
 
 	"github.com/tomaszmj/sudoku/board"
 	"github.com/tomaszmj/sudoku/set"
@@ -20,6 +22,9 @@ import (
 // fieldsToFill fieldsToFillHeap - priority queue to pick field to be filled in in each iteration,
 // leftoverChoices []fieldChoice - stack of choices that can be made when bactracking,
 // choicesMade []fieldChoice - stack of choices that were made, and will have to be reversed when backtracking.
+// Additionally, numbers present in each unit are counted (unitCounts, unitNumbers), so that possible values
+// of a field can be computed without visiting its neighbours, and each fieldToFill knows its index in
+// fieldsToFill, so that only fields affected by a change are moved in the heap (with heap.Fix).
 //
 // Each fieldToFill (element of fieldsToFillHeap) contains information about
 // its possible values (i.e. numbers that are not in the same row / column / subgrid).
@@ -32,6 +37,12 @@ import (
 // (after putting new number on the board there will be less possible choices for other fields to fill
 // in the same row / column / subgrid).
 //
+// Before making a choice between many possible values, we look for "hidden singles" - numbers that
+// can be placed only on one field of some row / column / subgrid / diagonal (see findHiddenSingle).
+// Such number is placed without pushing anything to leftoverChoices (just like the only possible value
+// of a field - "naked single"), because there is no other option. It is pushed to choicesMade though,
+// so that it is reverted when backtracking to choices made before it.
+//
 // If there are no possible values to choose from in one of the fieldsToFill, it means that given
 // solution space "subtree" cannot be solved - we try to do backtracking. In this case,
 // we revert all choices made (from choicesMade stack) until fields choice that was left on
@@ -49,48 +60,146 @@ import (
 type smartBacktrack struct {
 	board           *board.Board
 	fieldsToFill    fieldsToFillHeap
+	fields          []*fieldToFill // all fields of the board (by offset y*size+x), filled ones are not in fieldsToFill
+	unitsOfField    [][]int        // indexes of units (rows, columns, subgrids, diagonals and cages) of each field
+	unitCounts      [][]int        // number of occurrences of each number on the board in each unit
+	unitNumbers     []*set.Set     // numbers present on the board in each unit (with non-zero unitCounts)
+	units           [][]board.Field
+	hiddenSingles   [3]*set.Set // helper sets used by findHiddenSingle, allocated once in Reset
 	solvable        bool
 	leftoverChoices []fieldChoice
 	choicesMade     []fieldChoice
+	guesses         []int // indexes of guesses in choicesMade (depth of the search tree is their number)
+	stats           Stats
+	elapsed         time.Duration // time spent on searching since the last solution was found
+	hook            Hook
+	err             error // validation error of the initial board
 }
 
 func NewSmartBarcktrack() Solver {
 	return &smartBacktrack{}
 }
 
-func (s *smartBacktrack) Reset(board *board.Board) {
-	if err := s.validateInitialBoard(board); err != nil {
+func (s *smartBacktrack) Reset(b *board.Board) {
+	s.stats = Stats{}
+	s.elapsed = 0
+	s.err = b.CheckRules()
+	if s.err != nil {
 		s.solvable = false
 		return
 	}
 	s.solvable = true
-	s.board = board.Copy()
-	s.fieldsToFill = fieldsToFillHeap{}
-	board.ForEach(func(x, y int, n uint16) {
-		if n == 0 {
-			availableNumbers := s.findPossibleNumbers(x, y)
-			s.fieldsToFill = append(s.fieldsToFill, fieldToFill{x: x, y: y, possibleValues: availableNumbers})
+	s.board = b.Copy()
+	size := b.Size()
+	var units [][]board.Field
+	for _, unit := range b.Units() {
+		units = append(units, unit.Fields)
+	}
+	for _, cage := range b.Cages() {
+		units = append(units, cage.Fields)
+	}
+	s.units = units
+	s.hiddenSingles = [3]*set.Set{set.New(size), set.New(size), set.New(size)}
+	s.unitsOfField = make([][]int, size*size)
+	s.unitCounts = make([][]int, len(units))
+	s.unitNumbers = make([]*set.Set, len(units))
+	for i, fields := range units {
+		s.unitCounts[i] = make([]int, size+1)
+		s.unitNumbers[i] = set.New(size)
+		for _, f := range fields {
+			offset := f.Y*size + f.X
+			s.unitsOfField[offset] = append(s.unitsOfField[offset], i)
+		}
+	}
+	s.fields = make([]*fieldToFill, size*size)
+	b.ForEach(func(x, y int, n uint16) {
+		s.fields[y*size+x] = &fieldToFill{x: x, y: y, possibleValues: set.New(size), index: -1}
+		if n != 0 {
+			s.count(x, y, n, 1)
 		}
 	})
+	s.fieldsToFill = fieldsToFillHeap{}
+	for _, f := range s.fields {
+		if s.board.Get(f.x, f.y) == 0 {
+			s.computePossibleNumbers(f.x, f.y, f.possibleValues)
+			s.fieldsToFill.Push(f)
+		}
+	}
 	heap.Init(&s.fieldsToFill)
 	s.leftoverChoices = make([]fieldChoice, 0)
 	s.choicesMade = make([]fieldChoice, 0, len(s.fieldsToFill))
+	s.guesses = s.guesses[:0]
 }
 
 func (s *smartBacktrack) NextSolution() *board.Board {
+	solution, _ := s.NextSolutionContext(context.Background())
+	return solution
+}
+
+// NextSolutionContext can be called again after the search has been interrupted -
+// it is resumed from the place where it was stopped.
+func (s *smartBacktrack) NextSolutionContext(ctx context.Context) (*board.Board, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	var solution *board.Board
+	err := s.search(ctx, func(b *board.Board) {
+		solution = b.Copy()
+	})
+	return solution, err
+}
+
+// search looks for the next solution and calls onSolution with board
+// holding the solution if it is found. The board can be used only until
+// onSolution returns, because after that backtracking is performed
+// to prepare for searching the next solution.
+func (s *smartBacktrack) search(ctx context.Context, onSolution func(b *board.Board)) (err error) {
 	defer func() {
 		r := recover()
 		if r != nil {
 			fmt.Printf("panic encountered: %v\nboard:\n%s\n", r, s.board.String())
 			s.solvable = false
+			err = nil
 		}
 	}()
 	if !s.solvable {
 		return nil
 	}
-	for len(s.fieldsToFill) > 0 {
+	start := time.Now()
+	defer func() {
+		s.elapsed += time.Since(start)
+	}()
+	for i := 0; ; i++ {
+		if i%contextCheckInterval == 0 {
+			if err = contextDone(ctx); err != nil {
+				return err
+			}
+		}
+		if len(s.fieldsToFill) == 0 {
+			// constraints may not remove all candidates breaking them (see board.CandidatesRemover),
+			// so the whole board must be checked
+			if s.board.CheckConstraints() == nil {
+				break
+			}
+			if s.backtrack() {
+				continue
+			}
+			s.solvable = false
+			return nil
+		}
 		f := s.fieldsToFill[0]
-		if f.possibleValues.Len() == 0 {
+		noSolution := f.possibleValues.Len() == 0
+		if f.possibleValues.Len() > 1 {
+			// before branching, try to find number which can be placed only on one field of some unit
+			hiddenSingle, n, ok := s.findHiddenSingle()
+			if hiddenSingle != nil {
+				heap.Remove(&s.fieldsToFill, hiddenSingle.index)
+				s.setNumber(hiddenSingle.x, hiddenSingle.y, n, false)
+				continue
+			}
+			noSolution = !ok
+		}
+		if noSolution {
 			if s.backtrack() {
 				continue
 			} else {
@@ -98,18 +207,85 @@ func (s *smartBacktrack) NextSolution() *board.Board {
 				return nil
 			}
 		}
-		heap.Remove(&s.fieldsToFill, 0)
-		numberToSet := s.pickFirstAvailableNumber(&f)
-		s.setNumber(f.x, f.y, numberToSet)
+		guess := f.possibleValues.Len() > 1
+		heap.Pop(&s.fieldsToFill)
+		numberToSet := s.pickFirstAvailableNumber(f)
+		s.setNumber(f.x, f.y, numberToSet, guess)
 	}
-	solution := s.board.Copy()
+	// elapsed is increased by time since start when search returns,
+	// so that only time spent after finding the solution is counted for the next one
+	s.stats.SolutionTimes = append(s.stats.SolutionTimes, s.elapsed+time.Since(start))
+	s.elapsed = -time.Since(start)
+	s.event(Event{Type: EventSolution, Depth: len(s.guesses)})
+	onSolution(s.board)
 	if !s.backtrack() {
 		s.solvable = false // there will be no more solutions
 	}
-	return solution
+	return nil
+}
+
+// branchingPoint places naked and hidden singles (just like search does) until the first guess is needed.
+// It returns the field for which the guess has to be made, or nil if there is no need to guess -
+// in such case solved tells if the board has been filled in (it is a solution) or it has no solution.
+// It must be called right after Reset, for a board which does not break sudoku rules.
+func (s *smartBacktrack) branchingPoint() (f *fieldToFill, solved bool) {
+	for {
+		if len(s.fieldsToFill) == 0 {
+			return nil, s.board.CheckConstraints() == nil
+		}
+		f = s.fieldsToFill[0]
+		if f.possibleValues.Len() > 1 {
+			hiddenSingle, n, ok := s.findHiddenSingle()
+			if !ok {
+				return nil, false
+			}
+			if hiddenSingle == nil {
+				return f, false
+			}
+			heap.Remove(&s.fieldsToFill, hiddenSingle.index)
+			s.setNumber(hiddenSingle.x, hiddenSingle.y, n, false)
+			continue
+		}
+		if f.possibleValues.Len() == 0 {
+			return nil, false
+		}
+		heap.Pop(&s.fieldsToFill)
+		s.setNumber(f.x, f.y, s.pickFirstAvailableNumber(f), false)
+	}
+}
+
+func (s *smartBacktrack) Stats() Stats {
+	return s.stats
+}
+
+func (s *smartBacktrack) SetHook(hook Hook) {
+	s.hook = hook
+}
+
+// event sends e to the hook (if it is set).
+func (s *smartBacktrack) event(e Event) {
+	if s.hook != nil {
+		s.hook(e)
+	}
+}
+
+// placed updates statistics and sends event after number n has been put on field x, y
+// and pushed to choicesMade.
+func (s *smartBacktrack) placed(x, y int, n uint16, guess bool) {
+	s.stats.Nodes++
+	if !guess {
+		s.stats.Propagations++
+	}
+	s.stats.MaxDepth = maxInt(s.stats.MaxDepth, len(s.guesses))
+	s.stats.PeakChoicesMade = maxInt(s.stats.PeakChoicesMade, len(s.choicesMade))
+	s.stats.PeakLeftoverChoices = maxInt(s.stats.PeakLeftoverChoices, len(s.leftoverChoices))
+	s.event(Event{Type: EventPlace, X: x, Y: y, Number: n, Guess: guess, Depth: len(s.guesses)})
 }
 
 func (s *smartBacktrack) pickFirstAvailableNumber(f *fieldToFill) uint16 {
+	if f.possibleValues.Len() > 1 {
+		s.stats.Guesses++
+	}
 	var numberToSet uint16
 	f.possibleValues.ForEach(func(n int) bool {
 		if numberToSet == 0 {
@@ -122,28 +298,127 @@ func (s *smartBacktrack) pickFirstAvailableNumber(f *fieldToFill) uint16 {
 	return numberToSet
 }
 
-func (s *smartBacktrack) setNumber(x, y int, n uint16) {
-	s.board.Set(x, y, n)
+// findHiddenSingle looks for a unit with number n (not present in the unit), which is possible value
+// of only one fieldToFill in the unit - such number must be placed on that field. It returns the field
+// and n, or nil if there is no such unit. It returns false if there is a unit with number, which cannot
+// be placed on any field of the unit (so the board has no solution).
+// Only units with size fields are checked (all rows, columns, subgrids and diagonals, but usually not cages),
+// because only such units must contain all numbers.
+func (s *smartBacktrack) findHiddenSingle() (*fieldToFill, uint16, bool) {
+	size := s.board.Size()
+	once, twice, tmp := s.hiddenSingles[0], s.hiddenSingles[1], s.hiddenSingles[2]
+	for u, fields := range s.units {
+		if len(fields) != size {
+			continue
+		}
+		once.Clear()
+		twice.Clear()
+		for _, f := range fields {
+			field := s.fields[f.Y*size+f.X]
+			if field.index < 0 {
+				continue
+			}
+			tmp.Clear()
+			tmp.UnionWith(once)
+			tmp.IntersectWith(field.possibleValues)
+			twice.UnionWith(tmp)
+			once.UnionWith(field.possibleValues)
+		}
+		tmp.Fill()
+		tmp.Subtract(once)
+		tmp.Subtract(s.unitNumbers[u])
+		if tmp.Len() > 0 {
+			return nil, 0, false
+		}
+		once.Subtract(twice)
+		n := once.ForEach(func(int) bool { return true })
+		if n == 0 {
+			continue
+		}
+		for _, f := range fields {
+			field := s.fields[f.Y*size+f.X]
+			if field.index >= 0 && field.possibleValues.Get(n) {
+				return field, uint16(n), true
+			}
+		}
+	}
+	return nil, 0, true
+}
+
+func (s *smartBacktrack) setNumber(x, y int, n uint16, guess bool) {
+	s.place(x, y, n)
+	if guess {
+		s.guesses = append(s.guesses, len(s.choicesMade))
+	}
 	s.choicesMade = append(s.choicesMade, fieldChoice{x, y, n})
-	sortNeeded := false
-	for i := range s.fieldsToFill {
-		f := &s.fieldsToFill[i]
-		if f.x == x && f.y == y {
-			// this check will be removed, for now just a temporary brutal panic for tests
-			panic("assertion failed - setNumber while number is still in fieldsToFill")
+	s.placed(x, y, n, guess)
+}
+
+// place puts n on field x, y (which must not be in fieldsToFill)
+// and updates possible values of fieldsToFill affected by it.
+func (s *smartBacktrack) place(x, y int, n uint16) {
+	s.board.Set(x, y, n)
+	s.count(x, y, n, 1)
+	// field in the same row / column / subgrid / diagonal / cage cannot have n anymore
+	s.board.ForEachNeighbour(x, y, func(x, y int) {
+		f := s.fields[y*s.board.Size()+x]
+		if f.index >= 0 && f.possibleValues.Remove(int(n)) {
+			heap.Fix(&s.fieldsToFill, f.index)
 		}
-		// if field is in the same row / column / subgrid as changed field,
-		// set of possibleVelues must be updated
-		if f.x == x || f.y == y || s.board.HaveCommonSubgrid(x, y, f.x, f.y) {
-			removed := f.possibleValues.Remove(int(n))
-			sortNeeded = sortNeeded || removed
+	})
+	// if field is in the same cage or constraint as changed field, possible values must be
+	// recalculated, because cage sum / constraint limits them more than just removing n
+	s.forEachFieldWithCommonCageOrConstraint(x, y, s.updatePossibleNumbers)
+}
+
+// unplace removes number from field x, y (which must not be in fieldsToFill)
+// and updates possible values of fieldsToFill affected by it.
+func (s *smartBacktrack) unplace(x, y int) {
+	s.count(x, y, s.board.Get(x, y), -1)
+	s.board.Set(x, y, 0)
+	s.board.ForEachNeighbour(x, y, s.updatePossibleNumbers)
+	s.forEachFieldWithCommonCageOrConstraint(x, y, s.updatePossibleNumbers)
+}
+
+// count adds delta to count of n in each unit of field x, y.
+func (s *smartBacktrack) count(x, y int, n uint16, delta int) {
+	for _, unit := range s.unitsOfField[y*s.board.Size()+x] {
+		s.unitCounts[unit][n] += delta
+		if s.unitCounts[unit][n] > 0 {
+			s.unitNumbers[unit].Add(int(n))
+		} else {
+			s.unitNumbers[unit].Remove(int(n))
 		}
 	}
-	// TODO we can use heap.Fix only for changed fields if each field "knows" its queue index
-	// (but it is just an optimization that can be done later if needed)
-	if sortNeeded {
-		heap.Init(&s.fieldsToFill)
+}
+
+// updatePossibleNumbers recalculates possible values of field x, y if it is in fieldsToFill.
+func (s *smartBacktrack) updatePossibleNumbers(x, y int) {
+	f := s.fields[y*s.board.Size()+x]
+	if f.index < 0 {
+		return
+	}
+	s.computePossibleNumbers(x, y, f.possibleValues)
+	heap.Fix(&s.fieldsToFill, f.index)
+}
+
+// forEachFieldWithCommonCageOrConstraint calls operation for each field (other than x0, y0)
+// in the same cage or covered by the same constraint as x0, y0. Fields can be visited more than once.
+func (s *smartBacktrack) forEachFieldWithCommonCageOrConstraint(x0, y0 int, operation func(x, y int)) {
+	if cageIndex := s.board.CageIndex(x0, y0); cageIndex >= 0 {
+		for _, f := range s.board.Cages()[cageIndex].Fields {
+			if f.X != x0 || f.Y != y0 {
+				operation(f.X, f.Y)
+			}
+		}
 	}
+	s.board.ForEachConstraint(x0, y0, func(c board.Constraint) {
+		for _, f := range c.Fields() {
+			if f.X != x0 || f.Y != y0 {
+				operation(f.X, f.Y)
+			}
+		}
+	})
 }
 
 func (s *smartBacktrack) backtrack() bool {
@@ -153,9 +428,15 @@ func (s *smartBacktrack) backtrack() bool {
 	// pop the last leftover choice to backtrack to previous decision option
 	leftoverChoice := s.leftoverChoices[len(s.leftoverChoices)-1]
 	s.leftoverChoices = s.leftoverChoices[:len(s.leftoverChoices)-1]
+	s.stats.Backtracks++
 	// revert all choices made after setting something on leftoverChoice
 	for i := len(s.choicesMade) - 1; i >= 0; i-- {
 		f := s.choicesMade[i]
+		s.unplace(f.x, f.y)
+		if len(s.guesses) > 0 && s.guesses[len(s.guesses)-1] == i {
+			s.guesses = s.guesses[:len(s.guesses)-1]
+		}
+		s.event(Event{Type: EventBacktrack, X: f.x, Y: f.y, Number: f.n, Depth: len(s.guesses)})
 		// check if current f is the field that we want to backtrack to (use leftoverChoice for that)
 		if f.x == leftoverChoice.x && f.y == leftoverChoice.y {
 			// sanity check
@@ -163,59 +444,89 @@ func (s *smartBacktrack) backtrack() bool {
 			if !possibleNumbers.Get(int(leftoverChoice.n)) {
 				panic(fmt.Sprintf("backtrack possible numbers assertion failed: %d is not in possibleNumbers", leftoverChoice.n))
 			}
-
-			// just board.Set, not setNumber, because we are going to rebuild fieldsToFill from scratch anyway
-			s.board.Set(f.x, f.y, leftoverChoice.n)
-			// note that leftoverChoice must not be restired into fieldsToFill, because
+			// note that leftoverChoice must not be restored into fieldsToFill, because
 			// restoring it might cause the algorithm infinitely process the same subtree of choices
-			s.restoreFieldsToFill(s.choicesMade[(i + 1):])
+			s.place(f.x, f.y, leftoverChoice.n)
 			// change choicesMade - remove all that were after field to which we backtracked (f.x, f.y)
 			// and change number in the choice to which we backtracked
 			s.choicesMade = append(s.choicesMade[:i], fieldChoice{f.x, f.y, leftoverChoice.n})
+			s.guesses = append(s.guesses, i)
+			s.placed(f.x, f.y, leftoverChoice.n, true)
 			return true
 		}
-		// else - just set 0 on the board, fieldsToFill will be updated after reverting all choices
-		s.board.Set(f.x, f.y, 0)
+		// else - the field has to be filled in again
+		s.restoreFieldToFill(f.x, f.y)
 	}
 	panic("assertion failed in backtrack - restoredChoice coordinates were not in choicesMade")
 }
 
-// restoreFieldsToFill is a helper function for backtrack
-// It recreates fieldsToFill heap after choices from revertedChoices list
-// have been removed from the board.
-func (s *smartBacktrack) restoreFieldsToFill(revertedChoices []fieldChoice) {
-	for i := range s.fieldsToFill {
-		f := &s.fieldsToFill[i]
-		possibleValues := s.findPossibleNumbers(f.x, f.y)
-		s.fieldsToFill[i].possibleValues = possibleValues
-	}
-	for _, f := range revertedChoices {
-		possibleValues := s.findPossibleNumbers(f.x, f.y)
-		s.fieldsToFill = append(s.fieldsToFill, fieldToFill{f.x, f.y, possibleValues})
-	}
-	heap.Init(&s.fieldsToFill)
+// restoreFieldToFill is a helper function for backtrack.
+// It puts field x, y back to fieldsToFill after its number has been removed from the board.
+func (s *smartBacktrack) restoreFieldToFill(x, y int) {
+	f := s.fields[y*s.board.Size()+x]
+	s.computePossibleNumbers(x, y, f.possibleValues)
+	heap.Push(&s.fieldsToFill, f)
 }
 
 func (s *smartBacktrack) findPossibleNumbers(x, y int) *set.Set {
-	allForbiddenNumbers := set.New(s.board.Size())
-	s.board.ForEachNeighbour(x, y, func(x, y int) {
-		if n := s.board.Get(x, y); n != 0 {
-			allForbiddenNumbers.Add(int(n))
-		}
-	})
-	return allForbiddenNumbers.Complement()
+	possibleNumbers := set.New(s.board.Size())
+	s.computePossibleNumbers(x, y, possibleNumbers)
+	return possibleNumbers
 }
 
-func (s *smartBacktrack) validateInitialBoard(b *board.Board) error {
-	numbersFound := set.New(b.Size())
-	validateFunc := func(x, y int, n uint16) error {
-		if n == 0 {
-			return nil // initial validation accepts unfilled fields
+// computePossibleNumbers sets possibleNumbers to numbers that can be placed on empty field x, y.
+// Numbers present in units of the field are found with unitNumbers, so that its neighbours
+// do not have to be visited.
+func (s *smartBacktrack) computePossibleNumbers(x, y int, possibleNumbers *set.Set) {
+	possibleNumbers.Fill()
+	for _, unit := range s.unitsOfField[y*s.board.Size()+x] {
+		possibleNumbers.Subtract(s.unitNumbers[unit])
+	}
+	if cageIndex := s.board.CageIndex(x, y); cageIndex >= 0 {
+		s.removeCageSumConflicts(x, y, s.board.Cages()[cageIndex], possibleNumbers)
+	}
+	s.board.RemoveCandidates(x, y, possibleNumbers)
+}
+
+// removeCageSumConflicts removes from possibleNumbers of field x, y numbers, for which
+// the rest of the cage cannot be filled in so that numbers add up to the cage sum.
+// Number on field x, y itself is ignored (it is treated as empty), so that it can be used
+// for fields which are already set when backtracking.
+// For each number n we check if the remaining sum is between the sum of the smallest
+// and the sum of the largest numbers that can still be placed in the remaining empty fields.
+func (s *smartBacktrack) removeCageSumConflicts(x, y int, cage board.Cage, possibleNumbers *set.Set) {
+	remainingSum := cage.Sum
+	emptyFields := 0
+	used := set.New(s.board.Size())
+	for _, f := range cage.Fields {
+		if f.X == x && f.Y == y {
+			continue
 		}
-		if !numbersFound.Add(int(n)) {
-			return fmt.Errorf("number %d is repeated in row/column/subgrid at %d, %d", n, x, y)
+		if n := s.board.Get(f.X, f.Y); n != 0 {
+			remainingSum -= int(n)
+			used.Add(int(n))
+		} else {
+			emptyFields++
 		}
-		return nil
 	}
-	return b.Validate(validateFunc, numbersFound.Clear)
+	possibleNumbers.ForEach(func(n int) bool {
+		rest := remainingSum - n
+		minSum, maxSum := 0, 0
+		for i, smallest := 0, 1; i < emptyFields && smallest <= s.board.Size(); smallest++ {
+			if smallest != n && !used.Get(smallest) {
+				minSum += smallest
+				i++
+			}
+		}
+		for i, largest := 0, s.board.Size(); i < emptyFields && largest >= 1; largest-- {
+			if largest != n && !used.Get(largest) {
+				maxSum += largest
+				i++
+			}
+		}
+		if rest < minSum || rest > maxSum {
+			possibleNumbers.Remove(n)
+		}
+		return false
+	})
 }
@@ -1,8 +1,50 @@
 package solver
 
-import "github.com/tomaszmj/sudoku/board"
+import (
+	"context"
+
+	"github.com/tomaszmj/sudoku/board"
+)
 
 type Solver interface {
 	Reset(b *board.Board)
 	NextSolution() *board.Board
 }
+
+// ContextSolver is a Solver, which search can be interrupted.
+type ContextSolver interface {
+	Solver
+	// NextSolutionContext works like NextSolution, but the search is stopped
+	// as soon as ctx is done. In such case it returns nil and ctx.Err(), i.e.
+	// context.Canceled or context.DeadlineExceeded. If the board passed to Reset
+	// breaks sudoku rules, it returns nil and board.Violations error.
+	// If there are no more solutions, it returns nil, nil (just like NextSolution returns nil).
+	NextSolutionContext(ctx context.Context) (*board.Board, error)
+}
+
+// contextCheckInterval is number of search iterations after which solvers check if context is done.
+// Checking it in every iteration would noticeably slow down fast solvers.
+const contextCheckInterval = 1024
+
+// NextSolutionContext returns next solution from s, stopping when ctx is done
+// (see ContextSolver). If s does not implement ContextSolver, ctx is checked
+// only before calling s.NextSolution, which cannot be interrupted.
+func NextSolutionContext(ctx context.Context, s Solver) (*board.Board, error) {
+	if cs, ok := s.(ContextSolver); ok {
+		return cs.NextSolutionContext(ctx)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return s.NextSolution(), nil
+}
+
+// contextDone returns ctx.Err() if ctx is done, without blocking.
+func contextDone(ctx context.Context) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	default:
+		return nil
+	}
+}
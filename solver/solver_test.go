@@ -1,10 +1,13 @@
 package solver_test
 
 import (
+	"context"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"github.com/tomaszmj/sudoku/board"
 	"github.com/tomaszmj/sudoku/solver"
 )
 
@@ -57,8 +60,100 @@ func TestSmartBacktrack(t *testing.T) {
 	// normal solution is also hacked (it would not work for more complex
 	// puzzles with backtracking needed)
 	genericTestSolver(t, solver)
+	testDifficultPuzzle(t, solver)
+}
+
+// TestSmartBacktrackAllSolutions checks that smartBacktrack does not lose any solutions
+// when it backtracks, by comparing the number of all solutions with DLX.
+func TestSmartBacktrackAllSolutions(t *testing.T) {
+	countAll := func(s solver.Solver, b *board.Board) int {
+		s.Reset(b)
+		count := 0
+		for s.NextSolution() != nil {
+			count++
+		}
+		return count
+	}
+	for i, b := range boards9x9WithManySolutions {
+		expected := countAll(solver.NewDLX(), b)
+		assert.Greater(t, expected, 1, "board %d", i)
+		assert.Equal(t, expected, countAll(solver.NewSmartBarcktrack(), b), "board %d", i)
+	}
+}
+
+func TestSAT(t *testing.T) {
+	s := solver.NewSAT()
+	genericTestSolver(t, s)
+	testDifficultPuzzle(t, s)
+
+	t.Run("16x16 puzzle", func(t *testing.T) {
+		s.Reset(board16x16)
+		solution := s.NextSolution()
+		require.NotNil(t, solution)
+		assert.True(t, solution.Equal(board16x16Solution))
+		assert.Nil(t, s.NextSolution())
+	})
+
+	t.Run("enumerate all solutions", func(t *testing.T) {
+		empty, err := board.New(2, 2)
+		require.NoError(t, err)
+		s.Reset(empty)
+		solutions := make(map[string]bool)
+		for solution := s.NextSolution(); solution != nil; solution = s.NextSolution() {
+			solutions[solution.String()] = true
+		}
+		assert.Len(t, solutions, 288)
+	})
+
+	t.Run("board too large to encode", func(t *testing.T) {
+		large, err := board.New(8, 9)
+		require.NoError(t, err)
+		s.Reset(large)
+		solution, err := solver.NextSolutionContext(context.Background(), s)
+		assert.Nil(t, solution)
+		assert.Error(t, err)
+	})
+}
+
+func TestDLX(t *testing.T) {
+	dlx := solver.NewDLX()
+	genericTestSolver(t, dlx)
+	testDifficultPuzzle(t, dlx)
 
-	// this is not tested for Bruteforce because of computational complexity
+	t.Run("the same solutions as smartBacktrack", func(t *testing.T) {
+		smartBacktrack := solver.NewSmartBarcktrack()
+		for _, b := range []*board.Board{board6x6, board9x9Easy, board9x9Difficult, board12x12} {
+			dlx.Reset(b)
+			smartBacktrack.Reset(b)
+			solution := dlx.NextSolution()
+			require.NotNil(t, solution)
+			assert.Equal(t, smartBacktrack.NextSolution().String(), solution.String())
+			assert.Nil(t, dlx.NextSolution())
+		}
+	})
+
+	t.Run("16x16 puzzle", func(t *testing.T) {
+		dlx.Reset(board16x16)
+		solution := dlx.NextSolution()
+		require.NotNil(t, solution)
+		assert.True(t, solution.Equal(board16x16Solution))
+		assert.Nil(t, dlx.NextSolution())
+	})
+
+	t.Run("enumerate all solutions", func(t *testing.T) {
+		empty, err := board.New(2, 2)
+		require.NoError(t, err)
+		dlx.Reset(empty)
+		solutions := make(map[string]bool)
+		for solution := dlx.NextSolution(); solution != nil; solution = dlx.NextSolution() {
+			solutions[solution.String()] = true
+		}
+		assert.Len(t, solutions, 288) // number of all 4x4 sudoku grids
+	})
+}
+
+// testDifficultPuzzle is not run for Bruteforce because of computational complexity
+func testDifficultPuzzle(t *testing.T, solver solver.Solver) {
 	t.Run("difficult puzzle with 1 solution", func(t *testing.T) {
 		solver.Reset(difficultBoard)
 		solution := solver.NextSolution()
@@ -68,9 +163,201 @@ func TestSmartBacktrack(t *testing.T) {
 	})
 }
 
+func TestNextSolutionContext(t *testing.T) {
+	solvers := map[string]solver.Solver{
+		"bruteforce":     solver.NewBruteforce(),
+		"smartBacktrack": solver.NewSmartBarcktrack(),
+		"dlx":            solver.NewDLX(),
+		"sat":            solver.NewSAT(),
+		"parallel":       solver.NewParallel(2),
+	}
+	for name, s := range solvers {
+		t.Run(name, func(t *testing.T) {
+			t.Run("cancelled context", func(t *testing.T) {
+				ctx, cancel := context.WithCancel(context.Background())
+				cancel()
+				s.Reset(boardWithManySoltions)
+				solution, err := solver.NextSolutionContext(ctx, s)
+				assert.Nil(t, solution)
+				assert.ErrorIs(t, err, context.Canceled)
+
+				// the search can be continued after cancellation
+				i := 0
+				for solution, err = solver.NextSolutionContext(context.Background(), s); solution != nil; solution, err = solver.NextSolutionContext(context.Background(), s) {
+					i++
+				}
+				assert.NoError(t, err)
+				assert.Equal(t, 2, i)
+			})
+
+			t.Run("invalid puzzle", func(t *testing.T) {
+				s.Reset(invalidBoard)
+				solution, err := solver.NextSolutionContext(context.Background(), s)
+				assert.Nil(t, solution)
+				if name != "bruteforce" { // bruteforce does not validate initial board
+					var violations board.Violations
+					require.ErrorAs(t, err, &violations)
+					assert.Len(t, violations, 3)
+				}
+			})
+
+			t.Run("no more solutions", func(t *testing.T) {
+				s.Reset(solvedBoard)
+				solution, err := solver.NextSolutionContext(context.Background(), s)
+				require.NoError(t, err)
+				assert.Equal(t, solvedBoard.String(), solution.String())
+				solution, err = solver.NextSolutionContext(context.Background(), s)
+				assert.NoError(t, err)
+				assert.Nil(t, solution)
+			})
+		})
+	}
+
+	t.Run("deadline exceeded during search", func(t *testing.T) {
+		// empty 16x16 board has so many solutions that enumerating them takes forever
+		s := solver.NewSmartBarcktrack()
+		emptyBoard, err := board.New(4, 4)
+		require.NoError(t, err)
+		s.Reset(emptyBoard)
+		ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+		defer cancel()
+		start := time.Now()
+		for err == nil {
+			var solution *board.Board
+			solution, err = solver.NextSolutionContext(ctx, s)
+			if err != nil {
+				assert.Nil(t, solution)
+			} else {
+				require.NotNil(t, solution)
+			}
+		}
+		assert.ErrorIs(t, err, context.DeadlineExceeded)
+		assert.Less(t, time.Since(start), time.Second)
+	})
+
+	t.Run("solver without context support", func(t *testing.T) {
+		s := solver.NewLogical()
+		s.Reset(boardToSolve)
+		ctx, cancel := context.WithCancel(context.Background())
+		cancel()
+		_, err := solver.NextSolutionContext(ctx, s)
+		assert.ErrorIs(t, err, context.Canceled)
+		solution, err := solver.NextSolutionContext(context.Background(), s)
+		assert.NoError(t, err)
+		assert.Equal(t, solvedBoard.String(), solution.String())
+	})
+}
+
+func TestSmartBacktrackStats(t *testing.T) {
+	s := solver.NewSmartBarcktrack()
+	s.Reset(boardToSolve)
+	require.NotNil(t, s.NextSolution())
+	assert.Equal(t, 0, s.(solver.StatsReporter).Stats().Guesses)
+
+	// 12x12 board can be solved with naked and hidden singles only, so there is no need to guess
+	s.Reset(board12x12)
+	require.NotNil(t, s.NextSolution())
+	assert.Equal(t, 0, s.(solver.StatsReporter).Stats().Guesses)
+
+	s.Reset(difficultBoard)
+	require.NotNil(t, s.NextSolution())
+	stats := s.(solver.StatsReporter).Stats()
+	assert.Greater(t, stats.Guesses, 0)
+	assert.Greater(t, stats.Backtracks, 0)
+}
+
+func TestStatsAndHook(t *testing.T) {
+	solvers := map[string]solver.Solver{
+		"smartBacktrack": solver.NewSmartBarcktrack(),
+		"dlx":            solver.NewDLX(),
+	}
+	for name, s := range solvers {
+		t.Run(name, func(t *testing.T) {
+			for _, b := range []*board.Board{boardWithManySoltions, difficultBoard, diagonal6x6} {
+				// board is rebuilt from events, so that it can be compared with solutions
+				current := b.Copy()
+				var solutionsFromEvents []string
+				places, guesses, maxDepth := 0, 0, 0
+				s.(solver.Instrumented).SetHook(func(e solver.Event) {
+					switch e.Type {
+					case solver.EventPlace:
+						require.Equal(t, uint16(0), current.Get(e.X, e.Y))
+						current.Set(e.X, e.Y, e.Number)
+						places++
+						if e.Guess {
+							guesses++
+						}
+					case solver.EventBacktrack:
+						require.Equal(t, e.Number, current.Get(e.X, e.Y))
+						current.Set(e.X, e.Y, 0)
+					case solver.EventSolution:
+						solutionsFromEvents = append(solutionsFromEvents, current.String())
+					}
+					if e.Depth > maxDepth {
+						maxDepth = e.Depth
+					}
+				})
+				s.Reset(b)
+				var solutions []string
+				for solution := s.NextSolution(); solution != nil; solution = s.NextSolution() {
+					solutions = append(solutions, solution.String())
+				}
+				require.NotEmpty(t, solutions)
+				assert.Equal(t, solutions, solutionsFromEvents)
+
+				stats := s.(solver.StatsReporter).Stats()
+				assert.Equal(t, places, stats.Nodes)
+				assert.Equal(t, places-guesses, stats.Propagations)
+				assert.Equal(t, stats.Guesses+stats.Backtracks, guesses)
+				assert.Equal(t, maxDepth, stats.MaxDepth)
+				assert.Len(t, stats.SolutionTimes, len(solutions))
+				if name == "smartBacktrack" {
+					assert.Greater(t, stats.PeakChoicesMade, 0)
+				}
+			}
+			s.(solver.Instrumented).SetHook(nil)
+		})
+	}
+
+	t.Run("parallel", func(t *testing.T) {
+		p := solver.NewParallel(4)
+		defer p.Close()
+		solutionEvents := 0
+		p.SetHook(func(e solver.Event) {
+			if e.Type == solver.EventSolution {
+				solutionEvents++
+				p.Stats() // the hook may call back into the solver
+			}
+		})
+		empty, err := board.New(2, 2)
+		require.NoError(t, err)
+		p.Reset(empty)
+		solutions := 0
+		for solution := p.NextSolution(); solution != nil; solution = p.NextSolution() {
+			solutions++
+		}
+		assert.Equal(t, 288, solutions)
+		assert.Equal(t, 288, solutionEvents)
+		stats := p.Stats()
+		assert.Len(t, stats.SolutionTimes, 288)
+		assert.Greater(t, stats.Nodes, 0)
+		assert.Greater(t, stats.MaxDepth, 0)
+	})
+}
+
 func BenchmarkSmartBacktrack(b *testing.B) {
-	solver := solver.NewSmartBarcktrack()
+	benchmarkSolver(b, solver.NewSmartBarcktrack())
+}
+
+func BenchmarkSAT(b *testing.B) {
+	benchmarkSolver(b, solver.NewSAT())
+}
+
+func BenchmarkDLX(b *testing.B) {
+	benchmarkSolver(b, solver.NewDLX())
+}
 
+func benchmarkSolver(b *testing.B, solver solver.Solver) {
 	b.Run("6x6", func(b *testing.B) {
 		for i := 0; i < b.N; i++ {
 			solver.Reset(board6x6)
@@ -103,6 +390,14 @@ func BenchmarkSmartBacktrack(b *testing.B) {
 		}
 	})
 
+	b.Run("16x16", func(b *testing.B) {
+		for i := 0; i < b.N; i++ {
+			solver.Reset(board16x16)
+			require.NotNil(b, solver.NextSolution())
+			require.Nil(b, solver.NextSolution())
+		}
+	})
+
 	b.Run("25x25", func(b *testing.B) {
 		for i := 0; i < b.N; i++ {
 			solver.Reset(board25x25)
@@ -0,0 +1,97 @@
+package solver
+
+import "time"
+
+// Stats holds statistics of the search performed by solver since last Reset.
+type Stats struct {
+	// Guesses is number of times solver had to pick a number for a field, which had more than one possible value.
+	Guesses int
+	// Backtracks is number of times solver had to revert its choices and try another one.
+	Backtracks int
+	// Nodes is number of visited nodes of the search tree, i.e. numbers placed on the board
+	// (guessed, propagated or tried when backtracking).
+	Nodes int
+	// Propagations is number of numbers placed without guessing (because there was no other option).
+	Propagations int
+	// MaxDepth is maximum depth of the search tree reached, i.e. number of guesses made at the same time.
+	MaxDepth int
+	// PeakLeftoverChoices and PeakChoicesMade are maximum sizes of smartBacktrack stacks
+	// (see smartBacktrack). They are not reported by other solvers.
+	PeakLeftoverChoices int
+	PeakChoicesMade     int
+	// SolutionTimes is time spent on searching each solution found (since Reset or the previous solution).
+	SolutionTimes []time.Duration
+}
+
+// StatsReporter is implemented by solvers which can report statistics of their search.
+type StatsReporter interface {
+	Stats() Stats
+}
+
+// add adds statistics of another search (e.g. of a subtree searched by a Parallel worker) to s.
+// Counters are summed, maximums are combined, solution times are appended.
+func (s *Stats) add(other Stats) {
+	s.Guesses += other.Guesses
+	s.Backtracks += other.Backtracks
+	s.Nodes += other.Nodes
+	s.Propagations += other.Propagations
+	s.MaxDepth = maxInt(s.MaxDepth, other.MaxDepth)
+	s.PeakLeftoverChoices = maxInt(s.PeakLeftoverChoices, other.PeakLeftoverChoices)
+	s.PeakChoicesMade = maxInt(s.PeakChoicesMade, other.PeakChoicesMade)
+	s.SolutionTimes = append(s.SolutionTimes, other.SolutionTimes...)
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// EventType is type of search Event.
+type EventType int
+
+const (
+	// EventPlace is sent when solver places a number on the board.
+	EventPlace EventType = iota
+	// EventBacktrack is sent when solver removes a number from the board while backtracking.
+	EventBacktrack
+	// EventSolution is sent when solver finds a solution (before it is returned by NextSolution).
+	EventSolution
+)
+
+func (t EventType) String() string {
+	switch t {
+	case EventPlace:
+		return "place"
+	case EventBacktrack:
+		return "backtrack"
+	case EventSolution:
+		return "solution"
+	default:
+		return "unknown"
+	}
+}
+
+// Event describes a step of the search.
+type Event struct {
+	Type EventType
+	// X, Y and Number describe number placed or removed (they are not set for EventSolution).
+	X, Y   int
+	Number uint16
+	// Guess is true if the number placed has been chosen among many possible values
+	// (including the next choice tried when backtracking).
+	Guess bool
+	// Depth is depth of the search tree after the event (number of guesses made at the same time).
+	Depth int
+}
+
+// Hook is a function called by solver for each search Event. It is called synchronously,
+// so it slows down the search.
+type Hook func(e Event)
+
+// Instrumented is implemented by solvers which can send search events to a Hook.
+type Instrumented interface {
+	// SetHook sets hook called for each search event (nil disables it). It is kept after Reset.
+	SetHook(hook Hook)
+}